@@ -4,7 +4,13 @@ import (
 	"fmt"
 	"os"
 
+	"aiotype/internal/config"
+	"aiotype/internal/curriculum"
+	"aiotype/internal/history"
+	"aiotype/internal/lesson"
+	"aiotype/internal/theme"
 	"aiotype/internal/ui"
+	"aiotype/internal/ui/shared"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -27,18 +33,86 @@ func main() {
 		fmt.Println("aiotype - monkeytype but in Terminal")
 		fmt.Println()
 		fmt.Println("Usage:")
-		fmt.Println("  aiotype           Start the typing test")
-		fmt.Println("  aiotype --version Show version information")
-		fmt.Println("  aiotype --help    Show this help message")
+		fmt.Println("  aiotype                      Start the typing test")
+		fmt.Println("  aiotype --import-theme FILE  Import a monkeytype theme (CSS or JSON export)")
+		fmt.Println("  aiotype --version            Show version information")
+		fmt.Println("  aiotype --help               Show this help message")
 		fmt.Println()
 		return
 	}
 
-	model := ui.NewModel()
+	if len(os.Args) > 1 && os.Args[1] == "--import-theme" {
+		if len(os.Args) < 3 {
+			fmt.Println("Usage: aiotype --import-theme FILE")
+			os.Exit(1)
+		}
+		if err := importTheme(os.Args[2]); err != nil {
+			fmt.Printf("Error importing theme: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	settings, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: using default config: %v\n", err)
+	}
+
+	colorMode, err := shared.DetectColorMode(settings.Color)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	darkBackground := shared.DetectDarkBackground()
+	shared.SetColorMode(colorMode, darkBackground)
+
+	accessibility, err := shared.AccessibilityFromConfig(settings.Accessibility)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	shared.SetAccessibility(accessibility)
+
+	themeName := settings.Theme
+	if themeName == "" {
+		themeName = theme.DefaultName
+		if !darkBackground {
+			themeName = theme.DefaultLightName
+		}
+	}
+
+	registry := loadThemes()
+	if active, err := registry.Get(themeName); err == nil {
+		shared.SetTheme(active)
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	var store *history.Store
+	if path, err := config.HistoryPath(); err == nil {
+		store = history.NewStore(path)
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: results will not be saved: %v\n", err)
+	}
+
+	var lessons *lesson.Store
+	if path, err := config.LessonPath(); err == nil {
+		lessons = lesson.NewStore(path)
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: lesson progress will not be saved: %v\n", err)
+	}
+
+	var courses *curriculum.Store
+	if path, err := config.CurriculumPath(); err == nil {
+		courses = curriculum.NewStore(path)
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: curriculum progress will not be saved: %v\n", err)
+	}
+
+	model := ui.NewModel(settings, registry, store, lessons, courses)
 
 	program := tea.NewProgram(
 		model,
 		tea.WithAltScreen(),
+		tea.WithReportFocus(),
 	)
 
 	if _, err := program.Run(); err != nil {
@@ -46,3 +120,41 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+func loadThemes() *theme.Registry {
+	registry := theme.NewRegistry()
+
+	dir, err := config.ThemesDir()
+	if err != nil {
+		return registry
+	}
+
+	userThemes, err := theme.LoadDir(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	for _, t := range userThemes {
+		registry.Add(t)
+	}
+	return registry
+}
+
+func importTheme(path string) error {
+	t, err := theme.ImportMonkeytypeFile(path)
+	if err != nil {
+		return err
+	}
+
+	dir, err := config.ThemesDir()
+	if err != nil {
+		return err
+	}
+
+	saved, err := theme.SaveFile(dir, t)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported theme %q to %s\n", t.Name, saved)
+	return nil
+}
@@ -0,0 +1,322 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	appDirName      = "aiotype"
+	configFileName  = "config.toml"
+	themesDirName   = "themes"
+	historyFileName = "history.jsonl"
+	lessonFileName  = "lesson.json"
+	courseFileName  = "curriculum.json"
+)
+
+type Config struct {
+	// Theme is empty until the user picks one, so the default can follow
+	// the terminal's light or dark background.
+	Theme string `toml:"theme"`
+	Color string `toml:"color"`
+	Words int    `toml:"words"`
+	// Mode is "words" for random words, "quote" for one quote,
+	// "weakness" for words that practice what history shows is weakest or
+	// "lesson" for letter by letter touch typing lessons.
+	Mode      string    `toml:"mode"`
+	Quote     Quote     `toml:"quote"`
+	Generator Generator `toml:"generator"`
+	Lesson    Lesson    `toml:"lesson"`
+	// Difficulty is "normal", "expert" (fail on submitting a wrong word)
+	// or "master" (fail on any wrong keystroke).
+	Difficulty string `toml:"difficulty"`
+	// Funbox names the funboxes on every test, e.g. ["memory", "nospace"].
+	Funbox        []string      `toml:"funbox"`
+	VisibleLines  int           `toml:"visible_lines"`
+	Layout        string        `toml:"layout"`
+	Tape          Tape          `toml:"tape"`
+	Caret         Caret         `toml:"caret"`
+	QuickRestart  QuickRestart  `toml:"quick_restart"`
+	Input         Input         `toml:"input"`
+	Pause         Pause         `toml:"pause"`
+	Validity      Validity      `toml:"validity"`
+	Minimum       Minimum       `toml:"minimum"`
+	Accessibility Accessibility `toml:"accessibility"`
+	// Keys overrides key bindings per screen and action, for example
+	// [keys.menu] quit = ["ctrl+q"].
+	Keys map[string]map[string][]string `toml:"keys"`
+}
+
+type Quote struct {
+	Language string `toml:"language"`
+	// Length is "all", "short", "medium", "long" or "thicc".
+	Length        string `toml:"length"`
+	FavoritesOnly bool   `toml:"favorites_only"`
+	// Favorites and Ratings are keyed by quote, e.g. "english/12".
+	// Ratings run from 1 to 5.
+	Favorites []string       `toml:"favorites"`
+	Ratings   map[string]int `toml:"ratings"`
+}
+
+// Generator picks how words mode makes its text.
+type Generator struct {
+	// Kind is "random" for random common words or "markov" for
+	// sentence-like text from an n-gram model.
+	Kind string `toml:"kind"`
+	// Files are text files to train on instead of the built in prose.
+	Files []string `toml:"files"`
+	// Order is how many words of context pick the next one, 1 to 4.
+	Order int `toml:"order"`
+	// Coherence is the chance, from 0 to 1, that a word follows from the
+	// ones before it rather than being any word at all.
+	Coherence float64 `toml:"coherence"`
+	// Seed makes every test the same text when it is not zero.
+	Seed int64 `toml:"seed"`
+	// TopN draws random words from only the most common words of the
+	// built in list of a thousand. Zero keeps the original list of about
+	// three hundred everyday words.
+	TopN int `toml:"top_n"`
+	// Zipf weights random words by how common they are: 0 draws every
+	// word equally often, 1 as often as in real text.
+	Zipf float64 `toml:"zipf"`
+	// RepeatWindow keeps a word from repeating, or nearly repeating, any
+	// of the words this many before it. Zero allows repeats.
+	//
+	// By default words come uniformly from the original list, so default
+	// tests are as hard as they always were, with no word repeating the
+	// three before it. Something like top_n = 200 and zipf = 0.5 reads
+	// more like real text.
+	RepeatWindow int `toml:"repeat_window"`
+}
+
+// Lesson holds the targets of lesson mode. Every unlocked letter must
+// reach them before the next letter unlocks.
+type Lesson struct {
+	TargetWPM      float64 `toml:"target_wpm"`
+	TargetAccuracy float64 `toml:"target_accuracy"`
+}
+
+type QuickRestart struct {
+	// ConfirmAfter is how many seconds into a test leaving or restarting
+	// asks for confirmation. Zero never asks.
+	ConfirmAfter int `toml:"confirm_after"`
+}
+
+type Input struct {
+	// Paste is "reject" to ignore pasted text or "record" to type it and
+	// mark the result invalid.
+	Paste string `toml:"paste"`
+	// Freedom lets backspace reopen words that were typed correctly.
+	Freedom bool `toml:"freedom"`
+	// StopOnError is "off", "letter" or "word".
+	StopOnError string `toml:"stop_on_error"`
+	// Blind hides mistakes while typing; they still show on the results.
+	Blind bool `toml:"blind"`
+	// Lenient accepts plain keys for accented letters, ss for ß, straight
+	// quotes and - for dashes. IgnoreCase accepts either case.
+	Lenient    bool `toml:"lenient"`
+	IgnoreCase bool `toml:"ignore_case"`
+}
+
+type Pause struct {
+	// OnBlur pauses a running test when the terminal loses focus.
+	OnBlur bool `toml:"on_blur"`
+	// ExcludePaused leaves paused results out of personal bests and
+	// averages. They are still saved to history.
+	ExcludePaused bool `toml:"exclude_paused"`
+}
+
+// Validity holds the rules a finished test must pass to count towards
+// personal bests and averages. Zero turns a rule off.
+type Validity struct {
+	AFKSeconds      int     `toml:"afk_seconds"`
+	MinAccuracy     float64 `toml:"min_accuracy"`
+	MaxRawRatio     float64 `toml:"max_raw_ratio"`
+	MinTimingSpread float64 `toml:"min_timing_spread"`
+}
+
+// Minimum holds pace thresholds that fail a running test once the grace
+// period is over. Zero turns a threshold off.
+type Minimum struct {
+	WPM          float64 `toml:"wpm"`
+	Accuracy     float64 `toml:"accuracy"`
+	Burst        float64 `toml:"burst"`
+	GraceSeconds int     `toml:"grace_seconds"`
+}
+
+type Caret struct {
+	Style string `toml:"style"`
+	Blink bool   `toml:"blink"`
+	// Pace is "off", "wpm" (uses PaceWPM), "pb" or "average".
+	Pace    string  `toml:"pace"`
+	PaceWPM float64 `toml:"pace_wpm"`
+}
+
+type Tape struct {
+	Scroll string `toml:"scroll"`
+	// CaretColumn is where the caret sits, as a percentage of the width.
+	CaretColumn int `toml:"caret_column"`
+}
+
+type Accessibility struct {
+	// Colorblind is "none", "red-green" or "tritanopia". "deuteranopia"
+	// and "protanopia" are read as "red-green".
+	Colorblind   string `toml:"colorblind"`
+	HighContrast bool   `toml:"high_contrast"`
+	ErrorMarking string `toml:"error_marking"`
+	ExpectedChar string `toml:"expected_char"`
+}
+
+func Default() Config {
+	return Config{
+		Color:      "auto",
+		Words:      50,
+		Difficulty: "normal",
+		Mode:       "words",
+		Quote: Quote{
+			Language: "english",
+			Length:   "all",
+		},
+		Generator: Generator{
+			Kind:         "random",
+			Order:        2,
+			Coherence:    0.9,
+			RepeatWindow: 3,
+		},
+		Lesson: Lesson{
+			TargetWPM:      35,
+			TargetAccuracy: 95,
+		},
+		VisibleLines: 3,
+		Layout:       "box",
+		Tape: Tape{
+			Scroll:      "letter",
+			CaretColumn: 40,
+		},
+		QuickRestart: QuickRestart{
+			ConfirmAfter: 0,
+		},
+		Input: Input{
+			Paste:       "reject",
+			StopOnError: "off",
+		},
+		Pause: Pause{
+			OnBlur: true,
+		},
+		Minimum: Minimum{
+			GraceSeconds: 5,
+		},
+		Validity: Validity{
+			AFKSeconds:      15,
+			MinAccuracy:     75,
+			MaxRawRatio:     1.5,
+			MinTimingSpread: 0.1,
+		},
+		Caret: Caret{
+			Style:   "block",
+			Pace:    "off",
+			PaceWPM: 60,
+		},
+		Accessibility: Accessibility{
+			Colorblind:   "none",
+			ErrorMarking: "color",
+			ExpectedChar: "off",
+		},
+	}
+}
+
+func Dir() (string, error) {
+	if dir := os.Getenv("AIOTYPE_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, appDirName), nil
+}
+
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configFileName), nil
+}
+
+func ThemesDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, themesDirName), nil
+}
+
+func HistoryPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, historyFileName), nil
+}
+
+func LessonPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, lessonFileName), nil
+}
+
+func CurriculumPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, courseFileName), nil
+}
+
+// Load reads the config file, falling back to defaults for a missing file
+// and for any field the file leaves unset.
+func Load() (Config, error) {
+	cfg := Default()
+
+	path, err := Path()
+	if err != nil {
+		return cfg, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if _, err := toml.Decode(string(data), &cfg); err != nil {
+		return Default(), err
+	}
+	return cfg, nil
+}
+
+func Save(cfg Config) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(cfg); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
@@ -0,0 +1,302 @@
+// Package curriculum is a structured touch typing course: an ordered set
+// of lessons, each with generated exercises, pass criteria and the lessons
+// passing it unlocks. Lessons ship as TOML files under data/, read in file
+// name order, so a team can add its own by dropping in a file.
+package curriculum
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"aiotype/internal"
+)
+
+//go:embed data/*.toml
+var data embed.FS
+
+const defaultWords = 25
+
+var (
+	ErrInvalidLesson = errors.New("invalid lesson")
+	ErrUnknownLesson = errors.New("unknown lesson")
+	ErrInvalidStyle  = errors.New("invalid exercise style")
+)
+
+// Style is how a lesson builds its exercises.
+type Style int
+
+const (
+	// StyleWords types real words made only of the lesson's keys.
+	StyleWords Style = iota
+	// StyleDrills types short groups of the lesson's keys.
+	StyleDrills
+	// StyleMixed alternates real words and drills of the focus keys, e.g.
+	// words and numbers.
+	StyleMixed
+	// StylePunctuation types real words with the focus symbols attached.
+	StylePunctuation
+)
+
+func ParseStyle(value string) (Style, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "words":
+		return StyleWords, nil
+	case "drills":
+		return StyleDrills, nil
+	case "mixed":
+		return StyleMixed, nil
+	case "punctuation":
+		return StylePunctuation, nil
+	}
+	return StyleWords, fmt.Errorf("%w: %q", ErrInvalidStyle, value)
+}
+
+type Lesson struct {
+	ID          string `toml:"id"`
+	Title       string `toml:"title"`
+	Description string `toml:"description"`
+	// Keys are every key the exercises may use.
+	Keys string `toml:"keys"`
+	// Focus are the keys the lesson introduces, which every exercise word
+	// leans on. Empty practices all of Keys evenly.
+	Focus string `toml:"focus"`
+	// Style is "words", "drills", "mixed" or "punctuation".
+	Style string `toml:"style"`
+	// Words is how many words an exercise has.
+	Words int `toml:"words"`
+	// Capitals is the share of words, from 0 to 1, that start with a
+	// capital letter, for shift key practice.
+	Capitals     float64 `toml:"capitals"`
+	PassWPM      float64 `toml:"pass_wpm"`
+	PassAccuracy float64 `toml:"pass_accuracy"`
+	// Unlocks names the lessons passing this one opens.
+	Unlocks []string `toml:"unlocks"`
+
+	style Style
+}
+
+// Passes reports whether a result meets the lesson's pass criteria.
+func (l Lesson) Passes(result *internal.TestResult) bool {
+	return result != nil && result.Failed == "" && result.Invalid == "" &&
+		result.WPM >= l.PassWPM && result.Accuracy >= l.PassAccuracy
+}
+
+func (l *Lesson) validate() error {
+	if l.ID == "" {
+		return fmt.Errorf("%w: lesson %q has no id", ErrInvalidLesson, l.Title)
+	}
+	if l.Title == "" {
+		l.Title = l.ID
+	}
+	if l.Keys == "" {
+		return fmt.Errorf("%w: %s has no keys", ErrInvalidLesson, l.ID)
+	}
+	if l.Words <= 0 {
+		l.Words = defaultWords
+	}
+	if l.PassWPM <= 0 || l.PassAccuracy <= 0 || l.PassAccuracy > 100 {
+		return fmt.Errorf("%w: %s needs a pass_wpm and a pass_accuracy up to 100", ErrInvalidLesson, l.ID)
+	}
+	if l.Capitals < 0 || l.Capitals > 1 {
+		return fmt.Errorf("%w: %s capitals %.2f, want 0 to 1", ErrInvalidLesson, l.ID, l.Capitals)
+	}
+	style, err := ParseStyle(l.Style)
+	if err != nil {
+		return fmt.Errorf("%s: %w", l.ID, err)
+	}
+	l.style = style
+	return nil
+}
+
+type file struct {
+	Lessons []Lesson `toml:"lesson"`
+}
+
+// Curriculum is every lesson in course order.
+type Curriculum struct {
+	lessons []Lesson
+}
+
+// Load parses the embedded lessons. Lessons that do not parse or validate
+// are reported together and left out.
+func Load() (*Curriculum, error) {
+	files, err := fs.Glob(data, "data/*.toml")
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Curriculum{}
+	var errs []error
+	for _, name := range files {
+		content, err := data.ReadFile(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var f file
+		if _, err := toml.Decode(string(content), &f); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		for _, lesson := range f.Lessons {
+			if err := lesson.validate(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				continue
+			}
+			// A lesson whose keys make no words would start an empty
+			// exercise.
+			if len(lesson.Exercise(rand.New(rand.NewSource(1)))) == 0 {
+				errs = append(errs, fmt.Errorf("%s: %w: %s has no words to type", name, ErrInvalidLesson, lesson.ID))
+				continue
+			}
+			if _, err := c.Find(lesson.ID); err == nil {
+				errs = append(errs, fmt.Errorf("%s: %w: duplicate id %s", name, ErrInvalidLesson, lesson.ID))
+				continue
+			}
+			c.lessons = append(c.lessons, lesson)
+		}
+	}
+
+	for _, lesson := range c.lessons {
+		for _, id := range lesson.Unlocks {
+			if _, err := c.Find(id); err != nil {
+				errs = append(errs, fmt.Errorf("%s unlocks %w", lesson.ID, err))
+			}
+		}
+	}
+	return c, errors.Join(errs...)
+}
+
+func (c *Curriculum) Lessons() []Lesson {
+	return c.lessons
+}
+
+func (c *Curriculum) Find(id string) (Lesson, error) {
+	for _, lesson := range c.lessons {
+		if lesson.ID == id {
+			return lesson, nil
+		}
+	}
+	return Lesson{}, fmt.Errorf("%w: %q", ErrUnknownLesson, id)
+}
+
+// Unlocked reports whether a lesson is open: the first lesson always is,
+// and any other once a passed lesson unlocks it.
+func (c *Curriculum) Unlocked(progress *Progress, id string) bool {
+	if len(c.lessons) > 0 && c.lessons[0].ID == id {
+		return true
+	}
+	for _, lesson := range c.lessons {
+		if progress.Passed(lesson.ID) && slices.Contains(lesson.Unlocks, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Attempt is the best a typist has done at one lesson.
+type Attempt struct {
+	Passed       bool    `json:"passed"`
+	BestWPM      float64 `json:"best_wpm"`
+	BestAccuracy float64 `json:"best_accuracy"`
+	Tries        int     `json:"tries"`
+}
+
+type Progress struct {
+	Lessons map[string]Attempt `json:"lessons"`
+}
+
+func NewProgress() *Progress {
+	return &Progress{Lessons: map[string]Attempt{}}
+}
+
+func (p *Progress) Passed(id string) bool {
+	return p.Lessons[id].Passed
+}
+
+func (p *Progress) Clone() *Progress {
+	clone := NewProgress()
+	for id, attempt := range p.Lessons {
+		clone.Lessons[id] = attempt
+	}
+	return clone
+}
+
+// Record adds a finished exercise of lesson to the progress. It returns
+// whether this attempt passed and the lessons it newly unlocked.
+func (c *Curriculum) Record(progress *Progress, lesson Lesson, result *internal.TestResult) (bool, []Lesson) {
+	if progress.Lessons == nil {
+		progress.Lessons = map[string]Attempt{}
+	}
+	before := map[string]bool{}
+	for _, l := range c.lessons {
+		before[l.ID] = c.Unlocked(progress, l.ID)
+	}
+
+	attempt := progress.Lessons[lesson.ID]
+	attempt.Tries++
+	passed := lesson.Passes(result)
+	if passed {
+		attempt.Passed = true
+	}
+	if result != nil && result.Failed == "" && result.Invalid == "" {
+		attempt.BestWPM = max(attempt.BestWPM, result.WPM)
+		attempt.BestAccuracy = max(attempt.BestAccuracy, result.Accuracy)
+	}
+	progress.Lessons[lesson.ID] = attempt
+
+	var unlocked []Lesson
+	for _, l := range c.lessons {
+		if !before[l.ID] && c.Unlocked(progress, l.ID) {
+			unlocked = append(unlocked, l)
+		}
+	}
+	return passed, unlocked
+}
+
+// Store keeps the progress in a JSON file.
+type Store struct {
+	path string
+}
+
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load reads the saved progress, or starts the course afresh when there
+// is none.
+func (s *Store) Load() (*Progress, error) {
+	content, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewProgress(), nil
+	}
+	if err != nil {
+		return NewProgress(), err
+	}
+	progress := NewProgress()
+	if err := json.Unmarshal(content, progress); err != nil {
+		return NewProgress(), fmt.Errorf("%s: %w", s.path, err)
+	}
+	return progress, nil
+}
+
+func (s *Store) Save(progress *Progress) error {
+	content, err := json.MarshalIndent(progress, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, append(content, '\n'), 0o644)
+}
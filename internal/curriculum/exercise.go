@@ -0,0 +1,167 @@
+package curriculum
+
+import (
+	"math/rand"
+	"strings"
+	"unicode"
+
+	"aiotype/internal"
+)
+
+const (
+	// minPool is the fewest real words a words exercise needs before it
+	// mixes in drills to stay varied.
+	minPool = 8
+	// repeatWindow keeps a word from coming back too soon.
+	repeatWindow = 3
+	minDrill     = 2
+	maxDrill     = 5
+)
+
+// closers pairs the opening symbols that wrap a word with their closers.
+var closers = map[rune]rune{'(': ')', '[': ']', '{': '}', '"': '"', '<': '>'}
+
+// Exercise generates the words of one exercise of the lesson.
+func (l Lesson) Exercise(rng *rand.Rand) []string {
+	pool := l.pool()
+	var words []string
+	switch l.style {
+	case StyleDrills:
+		words = l.drills(l.Words, rng)
+	case StyleMixed:
+		words = l.mixed(pool, rng)
+	case StylePunctuation:
+		words = l.punctuate(l.draw(pool, l.Words, rng), rng)
+	default:
+		if len(pool) >= minPool {
+			words = l.draw(pool, l.Words, rng)
+		} else {
+			words = l.mixed(pool, rng)
+		}
+	}
+
+	for i, word := range words {
+		if rng.Float64() < l.Capitals {
+			runes := []rune(word)
+			runes[0] = unicode.ToUpper(runes[0])
+			words[i] = string(runes)
+		}
+	}
+	return words
+}
+
+// pool is the built in words typed only with the lesson's letters, and
+// with a focus letter when the focus has any.
+func (l Lesson) pool() []string {
+	letters := l.letters()
+	focus := lettersOf(l.Focus)
+	var pool []string
+	for _, word := range internal.RankedWords() {
+		if !onlyFrom(word, letters) {
+			continue
+		}
+		if focus != "" && !strings.ContainsAny(word, focus) {
+			continue
+		}
+		pool = append(pool, word)
+	}
+	return pool
+}
+
+// letters are the lesson's keys that appear in words, lower case.
+func (l Lesson) letters() string {
+	letters := lettersOf(l.Keys)
+	if strings.ContainsRune(l.Keys, '\'') {
+		letters += "'"
+	}
+	return letters
+}
+
+func lettersOf(keys string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(keys) {
+		if unicode.IsLetter(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func onlyFrom(word, letters string) bool {
+	for _, r := range word {
+		if !strings.ContainsRune(letters, r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (l Lesson) draw(pool []string, n int, rng *rand.Rand) []string {
+	weights := make([]float64, len(pool))
+	for i := range weights {
+		weights[i] = 1
+	}
+	return internal.DrawWords(pool, weights, repeatWindow, n, rng)
+}
+
+// drills are short groups of keys, drawn from the focus keys when there
+// are any.
+func (l Lesson) drills(n int, rng *rand.Rand) []string {
+	keys := []rune(l.Focus)
+	if len(keys) == 0 {
+		keys = []rune(l.Keys)
+	}
+	drills := make([]string, n)
+	for i := range drills {
+		group := make([]rune, minDrill+rng.Intn(maxDrill-minDrill+1))
+		for j := range group {
+			group[j] = keys[rng.Intn(len(keys))]
+		}
+		drills[i] = string(group)
+	}
+	return drills
+}
+
+// mixed alternates words from pool and drills. Without words it is all
+// drills.
+func (l Lesson) mixed(pool []string, rng *rand.Rand) []string {
+	if len(pool) == 0 {
+		return l.drills(l.Words, rng)
+	}
+	words := l.draw(pool, (l.Words+1)/2, rng)
+	drills := l.drills(l.Words/2, rng)
+	mixed := make([]string, 0, l.Words)
+	for i := range words {
+		mixed = append(mixed, words[i])
+		if i < len(drills) {
+			mixed = append(mixed, drills[i])
+		}
+	}
+	return mixed
+}
+
+// punctuate attaches a focus symbol to about half the words: openers wrap
+// the word and everything else follows it.
+func (l Lesson) punctuate(words []string, rng *rand.Rand) []string {
+	var symbols []rune
+	for _, r := range l.Focus {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			symbols = append(symbols, r)
+		}
+	}
+	if len(symbols) == 0 {
+		return words
+	}
+	for i, word := range words {
+		if rng.Intn(2) == 0 {
+			continue
+		}
+		symbol := symbols[rng.Intn(len(symbols))]
+		if closer, ok := closers[symbol]; ok {
+			words[i] = string(symbol) + word + string(closer)
+		} else {
+			words[i] = word + string(symbol)
+		}
+	}
+	return words
+}
@@ -0,0 +1,248 @@
+// Package funbox holds the modifiers that sit between word generation and
+// rendering, after monkeytype's funboxes. A funbox may rewrite the words of
+// a test, change how the text is laid out, or hide parts of it while
+// typing. Any number of them can be combined.
+package funbox
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+	"unicode"
+
+	"aiotype/internal"
+)
+
+var ErrUnknownFunbox = errors.New("unknown funbox")
+
+const (
+	// memoryDelay is how long the memory funbox shows the text.
+	memoryDelay = 5 * time.Second
+	// readAheadWords is how many words after the current one read ahead
+	// leaves visible.
+	readAheadWords = 3
+)
+
+// View is what a funbox sees when deciding whether to hide a character.
+type View struct {
+	Test *internal.TypingTest
+	// Shown is how long the test has been on screen.
+	Shown time.Duration
+}
+
+type Funbox struct {
+	Name        string
+	Description string
+	// Transform rewrites each generated word.
+	Transform func(word string, rng *rand.Rand) string
+	// NoSpace runs the words together.
+	NoSpace bool
+	// Mirror draws every word back to front while it is typed front to
+	// back.
+	Mirror bool
+	// Hide reports whether the untyped character at index is hidden.
+	Hide func(view View, index int) bool
+}
+
+// Builtin lists every funbox in menu order.
+func Builtin() []Funbox {
+	return []Funbox{
+		{
+			Name:        "random_case",
+			Description: "rAnDoMcAsE letters",
+			Transform:   randomCase,
+		},
+		{
+			Name:        "mirrored",
+			Description: "every word is drawn back to front",
+			Mirror:      true,
+		},
+		{
+			Name:        "reversed",
+			Description: "every word is spelled backwards",
+			Transform:   func(word string, _ *rand.Rand) string { return reverse(word) },
+		},
+		{
+			Name:        "capitals",
+			Description: "capital letters only",
+			Transform:   func(word string, _ *rand.Rand) string { return strings.ToUpper(word) },
+		},
+		{
+			Name:        "memory",
+			Description: "the text hides after a few seconds",
+			Hide: func(view View, _ int) bool {
+				return view.Shown > memoryDelay
+			},
+		},
+		{
+			Name:        "read_ahead",
+			Description: "only the next few words are visible",
+			Hide:        readAhead,
+		},
+		{
+			Name:        "nospace",
+			Description: "no spaces between words",
+			NoSpace:     true,
+		},
+		{
+			Name:        "58008",
+			Description: "numbers only",
+			Transform:   digits,
+		},
+	}
+}
+
+// Set is the funboxes active for a test, in menu order.
+type Set []Funbox
+
+// Parse looks up funboxes by name. Unknown names are reported and left
+// out, so one typo in the config does not drop the others.
+func Parse(names []string) (Set, error) {
+	builtin := Builtin()
+	wanted := make(map[string]bool, len(names))
+	var errs []error
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !known(builtin, name) {
+			errs = append(errs, fmt.Errorf("%w: %q", ErrUnknownFunbox, name))
+			continue
+		}
+		wanted[name] = true
+	}
+
+	var set Set
+	for _, f := range builtin {
+		if wanted[f.Name] {
+			set = append(set, f)
+		}
+	}
+	return set, errors.Join(errs...)
+}
+
+func known(builtin []Funbox, name string) bool {
+	for _, f := range builtin {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Set) Names() []string {
+	if len(s) == 0 {
+		return nil
+	}
+	names := make([]string, len(s))
+	for i, f := range s {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// Apply runs every transform over words and returns the new words.
+func (s Set) Apply(words []string, rng *rand.Rand) []string {
+	out := append([]string(nil), words...)
+	for _, f := range s {
+		if f.Transform == nil {
+			continue
+		}
+		for i, word := range out {
+			out[i] = f.Transform(word, rng)
+		}
+	}
+	return out
+}
+
+func (s Set) NoSpace() bool {
+	for _, f := range s {
+		if f.NoSpace {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Set) Mirrored() bool {
+	for _, f := range s {
+		if f.Mirror {
+			return true
+		}
+	}
+	return false
+}
+
+// Hidden reports whether the character at index is drawn blank. Typed
+// characters always stay visible.
+func (s Set) Hidden(view View, index int) bool {
+	if view.Test == nil || index < len(view.Test.TypedChars) {
+		return false
+	}
+	for _, f := range s {
+		if f.Hide != nil && f.Hide(view, index) {
+			return true
+		}
+	}
+	return false
+}
+
+// Source maps a column of the drawn text to the index in the target text
+// shown there. Only mirrored changes it, by flipping each word.
+func (s Set) Source(test *internal.TypingTest, index int) int {
+	if !s.Mirrored() || test == nil {
+		return index
+	}
+	unit := internal.GetWordIndexForPosition(test, index)
+	if unit == -1 {
+		return index
+	}
+	status := test.WordStatuses[unit]
+	return status.StartIndex + status.EndIndex - index
+}
+
+func randomCase(word string, rng *rand.Rand) string {
+	runes := []rune(word)
+	for i, r := range runes {
+		if rng.Intn(2) == 0 {
+			runes[i] = unicode.ToUpper(r)
+		} else {
+			runes[i] = unicode.ToLower(r)
+		}
+	}
+	return string(runes)
+}
+
+func reverse(word string) string {
+	runes := []rune(word)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// digits swaps a word for a number of the same length.
+func digits(word string, rng *rand.Rand) string {
+	length := len([]rune(word))
+	var b strings.Builder
+	for i := 0; i < length; i++ {
+		b.WriteByte(byte('0' + rng.Intn(10)))
+	}
+	return b.String()
+}
+
+// readAhead hides every word more than readAheadWords past the current one.
+func readAhead(view View, index int) bool {
+	test := view.Test
+	current := internal.GetWordIndexForPosition(test, test.CurrentPos)
+	unit := internal.GetWordIndexForPosition(test, index)
+	if current == -1 || unit == -1 {
+		return false
+	}
+	// Units alternate words and spaces unless the test has no spaces.
+	perWord := 2
+	if test.NoSpace {
+		perWord = 1
+	}
+	return unit/perWord-current/perWord > readAheadWords
+}
@@ -0,0 +1,304 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"aiotype/internal"
+)
+
+const AverageWindow = 10
+
+type Record struct {
+	CompletedAt  time.Time     `json:"completed_at"`
+	WPM          float64       `json:"wpm"`
+	Accuracy     float64       `json:"accuracy"`
+	TotalWords   int           `json:"total_words"`
+	CorrectWords int           `json:"correct_words"`
+	TotalChars   int           `json:"total_chars"`
+	CorrectChars int           `json:"correct_chars"`
+	ErrorCount   int           `json:"error_count"`
+	TestDuration time.Duration `json:"test_duration"`
+	WordCount    int           `json:"word_count"`
+	// Pauses is how often the test was paused; paused time is not part
+	// of TestDuration.
+	Pauses         int           `json:"pauses,omitempty"`
+	PausedDuration time.Duration `json:"paused_duration,omitempty"`
+	RawWPM         float64       `json:"raw_wpm,omitempty"`
+	// Invalid is why the test does not count, empty when it does.
+	Invalid string `json:"invalid,omitempty"`
+	// Failed is why a test ended early under expert or master
+	// difficulty, and FailedWord the word it ended on.
+	Failed     string   `json:"failed,omitempty"`
+	FailedWord string   `json:"failed_word,omitempty"`
+	Difficulty string   `json:"difficulty,omitempty"`
+	Funboxes   []string `json:"funboxes,omitempty"`
+	// Lenient marks tests typed with lenient matching, which rank apart.
+	Lenient bool `json:"lenient,omitempty"`
+	// QuoteID and QuoteLanguage name the quote of a quote mode test.
+	QuoteID       int    `json:"quote_id,omitempty"`
+	QuoteLanguage string `json:"quote_language,omitempty"`
+	// Mode is where the text came from, empty for words mode, and
+	// Exercise the curriculum lesson of an exercise.
+	Mode     string `json:"mode,omitempty"`
+	Exercise string `json:"exercise,omitempty"`
+	// Keys and Bigrams are keystroke statistics keyed by the expected
+	// characters, and MissedWords the words typed with an error. They
+	// feed weakness practice.
+	Keys        map[string]KeyStat `json:"keys,omitempty"`
+	Bigrams     map[string]KeyStat `json:"bigrams,omitempty"`
+	MissedWords []string           `json:"missed_words,omitempty"`
+}
+
+type KeyStat struct {
+	Hits   int           `json:"hits,omitempty"`
+	Misses int           `json:"misses,omitempty"`
+	Time   time.Duration `json:"time,omitempty"`
+	Timed  int           `json:"timed,omitempty"`
+}
+
+func NewRecord(result *internal.TestResult) Record {
+	record := Record{
+		CompletedAt:    result.CompletedAt,
+		WPM:            result.WPM,
+		Accuracy:       result.Accuracy,
+		TotalWords:     result.TotalWords,
+		CorrectWords:   result.CorrectWords,
+		TotalChars:     result.TotalChars,
+		CorrectChars:   result.CorrectChars,
+		ErrorCount:     result.ErrorCount,
+		TestDuration:   result.TestDuration,
+		WordCount:      result.TotalWords,
+		Pauses:         result.Pauses,
+		PausedDuration: result.PausedDuration,
+		RawWPM:         result.RawWPM,
+		Invalid:        result.Invalid,
+		Failed:         result.Failed,
+		FailedWord:     result.FailedWord,
+		Difficulty:     difficultyName(result.Difficulty),
+		Funboxes:       result.Funboxes,
+		Lenient:        result.Lenient.Enabled(),
+		Bigrams:        keyStats(result.KeyStats.Bigrams),
+		MissedWords:    result.MissedWords,
+		Mode:           modeName(result.Mode),
+		Exercise:       result.Exercise,
+	}
+	if result.Quote != nil {
+		record.QuoteID = result.Quote.ID
+		record.QuoteLanguage = result.Quote.Language
+	}
+	if len(result.KeyStats.Keys) > 0 {
+		record.Keys = make(map[string]KeyStat, len(result.KeyStats.Keys))
+		for key, stat := range result.KeyStats.Keys {
+			record.Keys[string(key)] = KeyStat(stat)
+		}
+	}
+	return record
+}
+
+func keyStats(stats map[string]internal.KeyStat) map[string]KeyStat {
+	if len(stats) == 0 {
+		return nil
+	}
+	converted := make(map[string]KeyStat, len(stats))
+	for key, stat := range stats {
+		converted[key] = KeyStat(stat)
+	}
+	return converted
+}
+
+// KeyStats turns the stored statistics back into the form they were
+// collected in. Records from before they were stored have none.
+func (r Record) KeyStats() internal.KeyStats {
+	stats := internal.KeyStats{
+		Keys:    make(map[rune]internal.KeyStat, len(r.Keys)),
+		Bigrams: make(map[string]internal.KeyStat, len(r.Bigrams)),
+	}
+	for key, stat := range r.Keys {
+		runes := []rune(key)
+		if len(runes) != 1 {
+			continue
+		}
+		stats.Keys[runes[0]] = internal.KeyStat(stat)
+	}
+	for bigram, stat := range r.Bigrams {
+		stats.Bigrams[bigram] = internal.KeyStat(stat)
+	}
+	return stats
+}
+
+// difficultyName leaves normal difficulty out of the file, so older
+// records and normal ones look the same.
+func difficultyName(d internal.Difficulty) string {
+	if d == internal.DifficultyNormal {
+		return ""
+	}
+	return d.String()
+}
+
+// modeName leaves words mode out of the file, so older records, which
+// were all words mode, and words mode ones look the same.
+func modeName(m internal.Mode) string {
+	if m == internal.ModeWords {
+		return ""
+	}
+	return m.String()
+}
+
+// Store appends records to a JSON Lines file, one completed test per line.
+type Store struct {
+	path string
+}
+
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+func (s *Store) Load() ([]Record, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return records, fmt.Errorf("%s:%d: %w", s.path, line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+func (s *Store) Append(record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// Valid drops records that failed the validity rules. They stay in the
+// history file but never count towards personal bests or averages.
+func Valid(records []Record) []Record {
+	var kept []Record
+	for _, record := range records {
+		if record.Invalid == "" {
+			kept = append(kept, record)
+		}
+	}
+	return kept
+}
+
+// Passed drops failed tests. Failures are kept apart from finished tests
+// and never count towards personal bests or averages.
+func Passed(records []Record) []Record {
+	var kept []Record
+	for _, record := range records {
+		if record.Failed == "" {
+			kept = append(kept, record)
+		}
+	}
+	return kept
+}
+
+// Failures is the other half of Passed.
+func Failures(records []Record) []Record {
+	var failed []Record
+	for _, record := range records {
+		if record.Failed != "" {
+			failed = append(failed, record)
+		}
+	}
+	return failed
+}
+
+// WithoutPaused drops records of tests that were paused, for rankings that
+// only count uninterrupted runs.
+func WithoutPaused(records []Record) []Record {
+	var kept []Record
+	for _, record := range records {
+		if record.Pauses == 0 {
+			kept = append(kept, record)
+		}
+	}
+	return kept
+}
+
+// Lenient keeps the records typed with lenient matching on or off, so
+// each has its own personal bests.
+func Lenient(records []Record, lenient bool) []Record {
+	var kept []Record
+	for _, record := range records {
+		if record.Lenient == lenient {
+			kept = append(kept, record)
+		}
+	}
+	return kept
+}
+
+// OfMode keeps the records typed in mode, since text from different
+// sources is not comparable.
+func OfMode(records []Record, mode internal.Mode) []Record {
+	var kept []Record
+	for _, record := range records {
+		if record.Mode == modeName(mode) {
+			kept = append(kept, record)
+		}
+	}
+	return kept
+}
+
+func PersonalBest(records []Record) float64 {
+	best := 0.0
+	for _, record := range records {
+		if record.WPM > best {
+			best = record.WPM
+		}
+	}
+	return best
+}
+
+// AverageWPM averages the most recent n records, or all of them if there
+// are fewer than n.
+func AverageWPM(records []Record, n int) float64 {
+	if len(records) == 0 || n <= 0 {
+		return 0
+	}
+	if len(records) > n {
+		records = records[len(records)-n:]
+	}
+
+	total := 0.0
+	for _, record := range records {
+		total += record.WPM
+	}
+	return total / float64(len(records))
+}
@@ -0,0 +1,125 @@
+package internal
+
+import "time"
+
+// keyStatsMaxInterval is the longest gap before a key that still counts
+// towards its speed. Longer gaps are pauses to think, not slow fingers.
+const keyStatsMaxInterval = 2 * time.Second
+
+// KeyStat counts how a key, or a pair of keys, went in one test.
+type KeyStat struct {
+	Hits   int
+	Misses int
+	// Time is the time spent reaching the key over the hits that were
+	// timed. Timed counts them, since the first key of a test and keys
+	// after a long gap have no useful time.
+	Time  time.Duration
+	Timed int
+}
+
+func (s KeyStat) Add(other KeyStat) KeyStat {
+	return KeyStat{
+		Hits:   s.Hits + other.Hits,
+		Misses: s.Misses + other.Misses,
+		Time:   s.Time + other.Time,
+		Timed:  s.Timed + other.Timed,
+	}
+}
+
+// MissRate is the share of attempts that were wrong.
+func (s KeyStat) MissRate() float64 {
+	attempts := s.Hits + s.Misses
+	if attempts == 0 {
+		return 0
+	}
+	return float64(s.Misses) / float64(attempts)
+}
+
+// Average is the mean time to reach the key, or zero when it was never
+// timed.
+func (s KeyStat) Average() time.Duration {
+	if s.Timed == 0 {
+		return 0
+	}
+	return s.Time / time.Duration(s.Timed)
+}
+
+// KeyStats are the per key statistics of one test, keyed by the
+// character that should have been typed.
+type KeyStats struct {
+	Keys map[rune]KeyStat
+	// Bigrams are keyed by two characters, and count against the second
+	// one: typing "th" as "tg" misses "th".
+	Bigrams map[string]KeyStat
+}
+
+// CollectKeyStats walks the keystrokes of a test, including ones later
+// deleted and keys refused by stop on error. Pasted text and spaces are
+// left out, since neither says anything about the typist's fingers.
+func CollectKeyStats(test *TypingTest) KeyStats {
+	stats := KeyStats{Keys: map[rune]KeyStat{}, Bigrams: map[string]KeyStat{}}
+	if test == nil {
+		return stats
+	}
+
+	var previous *Keystroke
+	for i := range test.Keystrokes {
+		keystroke := &test.Keystrokes[i]
+		last := previous
+		previous = keystroke
+		if keystroke.Kind != KeystrokeChar || keystroke.Pasted || keystroke.Expected == ' ' {
+			continue
+		}
+
+		var stat KeyStat
+		if keystroke.IsCorrect && !keystroke.Rejected {
+			stat.Hits = 1
+			if last != nil {
+				if gap := keystroke.Offset - last.Offset; gap <= keyStatsMaxInterval {
+					stat.Time, stat.Timed = gap, 1
+				}
+			}
+		} else {
+			stat.Misses = 1
+		}
+		stats.Keys[keystroke.Expected] = stats.Keys[keystroke.Expected].Add(stat)
+
+		if last != nil && last.Kind == KeystrokeChar && !last.Pasted && last.Expected != ' ' {
+			bigram := string([]rune{last.Expected, keystroke.Expected})
+			stats.Bigrams[bigram] = stats.Bigrams[bigram].Add(stat)
+		}
+	}
+	return stats
+}
+
+// KeyTimings times each correct character of the text from the
+// timestamps of its TypedChar, keyed by the character that should have
+// been typed. Misses count every wrong key, including ones deleted or
+// refused, since a corrected mistake is still a mistake while learning.
+func KeyTimings(test *TypingTest) map[rune]KeyStat {
+	timings := map[rune]KeyStat{}
+	if test == nil {
+		return timings
+	}
+
+	target := []rune(test.TargetText)
+	for i, typed := range test.TypedChars {
+		if i >= len(target) || target[i] == ' ' || !typed.IsCorrect {
+			continue
+		}
+		stat := KeyStat{Hits: 1}
+		if i > 0 {
+			if gap := typed.Timestamp.Sub(test.TypedChars[i-1].Timestamp); gap <= keyStatsMaxInterval {
+				stat.Time, stat.Timed = gap, 1
+			}
+		}
+		timings[target[i]] = timings[target[i]].Add(stat)
+	}
+	for _, keystroke := range test.Keystrokes {
+		wrong := !keystroke.IsCorrect || keystroke.Rejected
+		if keystroke.Kind == KeystrokeChar && wrong && !keystroke.Pasted && keystroke.Expected != ' ' {
+			timings[keystroke.Expected] = timings[keystroke.Expected].Add(KeyStat{Misses: 1})
+		}
+	}
+	return timings
+}
@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Lenient loosens how typed keys are matched against the text, for typing
+// other languages on a keyboard that lacks their letters.
+type Lenient struct {
+	// Fold accepts plain letters for accented ones, ss for ß, straight
+	// quotes for typographic ones and - for dashes.
+	Fold bool
+	// IgnoreCase accepts either case.
+	IgnoreCase bool
+}
+
+func (l Lenient) Enabled() bool {
+	return l.Fold || l.IgnoreCase
+}
+
+// foldedRunes are characters that do not decompose into a plain letter and
+// a mark but are still typed as one plain key.
+var foldedRunes = map[rune]rune{
+	'‘': '\'', '’': '\'', '‚': '\'', '‹': '\'', '›': '\'',
+	'“': '"', '”': '"', '„': '"', '«': '"', '»': '"',
+	'‐': '-', '‑': '-', '–': '-', '—': '-', '−': '-',
+	'ø': 'o', 'Ø': 'O', 'ł': 'l', 'Ł': 'L', 'đ': 'd', 'Đ': 'D', 'ı': 'i',
+	' ': ' ',
+}
+
+// foldedSequences are characters typed as more than one plain key.
+var foldedSequences = map[rune]string{
+	'ß': "ss", 'ẞ': "SS",
+	'æ': "ae", 'Æ': "AE",
+	'œ': "oe", 'Œ': "OE",
+	'…': "...",
+}
+
+// Matches reports whether typing char counts as typing expected.
+func (l Lenient) Matches(char, expected rune) bool {
+	if char == expected {
+		return true
+	}
+	if l.Fold {
+		char, expected = fold(char), fold(expected)
+	}
+	if l.IgnoreCase {
+		return unicode.ToLower(char) == unicode.ToLower(expected)
+	}
+	return char == expected
+}
+
+// sequence is the keys that type expected when it takes more than one,
+// or nil.
+func (l Lenient) sequence(expected rune) []rune {
+	if !l.Fold {
+		return nil
+	}
+	if keys, ok := foldedSequences[expected]; ok {
+		return []rune(keys)
+	}
+	return nil
+}
+
+// fold strips r down to the plain key that types it, e.g. é to e.
+func fold(r rune) rune {
+	if folded, ok := foldedRunes[r]; ok {
+		return folded
+	}
+	decomposed := []rune(norm.NFD.String(string(r)))
+	if len(decomposed) < 2 || IsCombiningMark(decomposed[0]) {
+		return r
+	}
+	for _, mark := range decomposed[1:] {
+		if !IsCombiningMark(mark) {
+			return r
+		}
+	}
+	return decomposed[0]
+}
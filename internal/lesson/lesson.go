@@ -0,0 +1,213 @@
+// Package lesson teaches touch typing a few letters at a time, after
+// keybr. Tests are pseudo-words made only of the unlocked letters, and
+// once every unlocked letter is typed fast and accurately enough the next
+// one unlocks. Progress is saved between sessions.
+package lesson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"aiotype/internal"
+)
+
+// Alphabet is the order letters unlock in, the most common first.
+const Alphabet = "enitrlasoudychgpmkbwfzvxqj"
+
+const (
+	// StartLetters is how many letters a new course starts with.
+	StartLetters = 6
+	// minSamples is how many times a letter must be typed before it can
+	// count as learned.
+	minSamples = 20
+	// smoothing is how much the latest test moves a letter's speed and
+	// accuracy, so one good or bad test does not decide an unlock.
+	smoothing = 0.3
+)
+
+var ErrInvalidTarget = errors.New("invalid lesson target")
+
+// Targets are the speed and accuracy every unlocked letter must reach.
+type Targets struct {
+	WPM      float64
+	Accuracy float64
+}
+
+func DefaultTargets() Targets {
+	return Targets{WPM: 35, Accuracy: 95}
+}
+
+func (t Targets) Validate() error {
+	if t.WPM <= 0 {
+		return fmt.Errorf("%w: %.0f wpm", ErrInvalidTarget, t.WPM)
+	}
+	if t.Accuracy <= 0 || t.Accuracy > 100 {
+		return fmt.Errorf("%w: %.0f%% accuracy", ErrInvalidTarget, t.Accuracy)
+	}
+	return nil
+}
+
+// KeyTime is the time per key at the target speed.
+func (t Targets) KeyTime() time.Duration {
+	return time.Duration(float64(time.Minute) / (t.WPM * internal.CharsPerWord))
+}
+
+// Key is the running speed and accuracy of one letter.
+type Key struct {
+	// Time is the smoothed time to reach the letter, zero until it has
+	// been timed.
+	Time     time.Duration `json:"time,omitempty"`
+	Accuracy float64       `json:"accuracy"`
+	Samples  int           `json:"samples"`
+}
+
+func (k Key) Learned(targets Targets) bool {
+	return k.Samples >= minSamples && k.Time > 0 &&
+		k.Time <= targets.KeyTime() && k.Accuracy >= targets.Accuracy
+}
+
+// shortfall is how far the letter is from its targets, as a multiple of
+// them. Letters not yet typed enough are the furthest of all.
+func (k Key) shortfall(targets Targets) float64 {
+	if k.Samples < minSamples || k.Time == 0 {
+		return math.Inf(1)
+	}
+	speed := float64(k.Time) / float64(targets.KeyTime())
+	accuracy := (100 - k.Accuracy) / math.Max(100-targets.Accuracy, 1)
+	return math.Max(speed, accuracy)
+}
+
+// WPM is the speed the letter is typed at.
+func (k Key) WPM() float64 {
+	if k.Time == 0 {
+		return 0
+	}
+	return float64(time.Minute) / float64(k.Time) / internal.CharsPerWord
+}
+
+type Progress struct {
+	Unlocked int            `json:"unlocked"`
+	Keys     map[string]Key `json:"keys"`
+}
+
+func NewProgress() *Progress {
+	return &Progress{Unlocked: StartLetters, Keys: map[string]Key{}}
+}
+
+func (p *Progress) Clone() *Progress {
+	clone := &Progress{Unlocked: p.Unlocked, Keys: make(map[string]Key, len(p.Keys))}
+	for letter, key := range p.Keys {
+		clone.Keys[letter] = key
+	}
+	return clone
+}
+
+// Letters are the unlocked letters, in unlock order.
+func (p *Progress) Letters() []rune {
+	return []rune(Alphabet)[:min(max(p.Unlocked, StartLetters), len(Alphabet))]
+}
+
+func (p *Progress) Complete() bool {
+	return p.Unlocked >= len(Alphabet)
+}
+
+func (p *Progress) Key(letter rune) Key {
+	return p.Keys[string(letter)]
+}
+
+// Focus is the unlocked letter furthest from its targets, which lessons
+// lean on. Ties go to the letter unlocked last. It is zero once every
+// letter is learned.
+func (p *Progress) Focus(targets Targets) rune {
+	var focus rune
+	furthest := 0.0
+	for _, letter := range p.Letters() {
+		key := p.Key(letter)
+		if key.Learned(targets) {
+			continue
+		}
+		if shortfall := key.shortfall(targets); shortfall >= furthest {
+			focus, furthest = letter, shortfall
+		}
+	}
+	return focus
+}
+
+// Record folds the key timings of a finished test into the progress and
+// unlocks the next letter once every unlocked one is learned. It returns
+// the letter unlocked, or zero.
+func (p *Progress) Record(timings map[rune]internal.KeyStat, targets Targets) rune {
+	if p.Keys == nil {
+		p.Keys = map[string]Key{}
+	}
+	for _, letter := range p.Letters() {
+		stat, ok := timings[letter]
+		if !ok || stat.Hits+stat.Misses == 0 {
+			continue
+		}
+		key := p.Key(letter)
+		accuracy := 100 * (1 - stat.MissRate())
+		if key.Samples == 0 {
+			key.Accuracy = accuracy
+		} else {
+			key.Accuracy += smoothing * (accuracy - key.Accuracy)
+		}
+		if average := stat.Average(); average > 0 {
+			if key.Time == 0 {
+				key.Time = average
+			} else {
+				key.Time += time.Duration(smoothing * float64(average-key.Time))
+			}
+		}
+		key.Samples += stat.Hits + stat.Misses
+		p.Keys[string(letter)] = key
+	}
+
+	if p.Complete() || p.Focus(targets) != 0 {
+		return 0
+	}
+	p.Unlocked = len(p.Letters()) + 1
+	return []rune(Alphabet)[p.Unlocked-1]
+}
+
+// Store keeps the progress in a JSON file.
+type Store struct {
+	path string
+}
+
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load reads the saved progress, or starts a new course when there is
+// none.
+func (s *Store) Load() (*Progress, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewProgress(), nil
+	}
+	if err != nil {
+		return NewProgress(), err
+	}
+	progress := NewProgress()
+	if err := json.Unmarshal(data, progress); err != nil {
+		return NewProgress(), fmt.Errorf("%s: %w", s.path, err)
+	}
+	return progress, nil
+}
+
+func (s *Store) Save(progress *Progress) error {
+	data, err := json.MarshalIndent(progress, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,115 @@
+package lesson
+
+import (
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"aiotype/internal"
+)
+
+const (
+	minWordLength = 3
+	maxWordLength = 7
+	// focusAttempts is how many pseudo-words are tried to find one with
+	// the focus letter in it.
+	focusAttempts = 10
+	// wordStart and wordEnd stand for the edges of a word in the letter
+	// model.
+	wordStart = '^'
+	wordEnd   = '$'
+)
+
+var (
+	transitionsOnce sync.Once
+	// transitions counts which letter follows which in the built in
+	// words, so pseudo-words read like English rather than noise.
+	transitions map[rune]map[rune]int
+)
+
+func letterModel() map[rune]map[rune]int {
+	transitionsOnce.Do(func() {
+		transitions = map[rune]map[rune]int{}
+		for _, word := range internal.RankedWords() {
+			previous := rune(wordStart)
+			for _, r := range word + string(wordEnd) {
+				if r != wordEnd && !strings.ContainsRune(Alphabet, r) {
+					previous = wordStart
+					continue
+				}
+				if transitions[previous] == nil {
+					transitions[previous] = map[rune]int{}
+				}
+				transitions[previous][r]++
+				previous = r
+			}
+		}
+	})
+	return transitions
+}
+
+// Generator makes pseudo-words from the unlocked letters, leaning on the
+// focus letter.
+type Generator struct {
+	letters []rune
+	focus   rune
+}
+
+func NewGenerator(letters []rune, focus rune) *Generator {
+	return &Generator{letters: letters, focus: focus}
+}
+
+func (g *Generator) Words(n int) []string {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	words := make([]string, 0, n)
+	for len(words) < n {
+		var word string
+		for attempt := 0; attempt < focusAttempts; attempt++ {
+			word = g.pseudoWord(rng)
+			if (g.focus == 0 || strings.ContainsRune(word, g.focus)) &&
+				(len(words) == 0 || word != words[len(words)-1]) {
+				break
+			}
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// pseudoWord walks the letter model over the unlocked letters only. Every
+// letter gets a small weight after every other, so a lesson of letters
+// that rarely meet in English still has words to type.
+func (g *Generator) pseudoWord(rng *rand.Rand) string {
+	model := letterModel()
+	var word []rune
+	previous := rune(wordStart)
+	for len(word) < maxWordLength {
+		candidates := append([]rune(nil), g.letters...)
+		if len(word) >= minWordLength {
+			candidates = append(candidates, wordEnd)
+		}
+		weights := make([]int, len(candidates))
+		total := 0
+		for i, r := range candidates {
+			weights[i] = 1 + model[previous][r]
+			total += weights[i]
+		}
+
+		pick := rng.Intn(total)
+		next := candidates[len(candidates)-1]
+		for i, weight := range weights {
+			if pick < weight {
+				next = candidates[i]
+				break
+			}
+			pick -= weight
+		}
+		if next == wordEnd {
+			break
+		}
+		word = append(word, next)
+		previous = next
+	}
+	return string(word)
+}
@@ -0,0 +1,144 @@
+// Package markov generates sentence-like text from an n-gram model of real
+// prose, so tests practice the word transitions of actual writing rather
+// than a salad of common words.
+package markov
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+)
+
+//go:embed corpus.txt
+var corpus string
+
+var (
+	ErrInvalidKind      = errors.New("invalid generator")
+	ErrInvalidOrder     = errors.New("invalid markov order")
+	ErrInvalidCoherence = errors.New("invalid coherence")
+	ErrEmptyCorpus      = errors.New("corpus has too few words")
+)
+
+const (
+	MinOrder = 1
+	MaxOrder = 4
+)
+
+// Corpus is the embedded prose the generator trains on by default.
+func Corpus() string {
+	return corpus
+}
+
+// LoadFiles reads the user's own text files to train on. Files that
+// cannot be read are reported together and skipped.
+func LoadFiles(paths []string) ([]string, error) {
+	var texts []string
+	var errs []error
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		texts = append(texts, string(data))
+	}
+	return texts, errors.Join(errs...)
+}
+
+// Chain maps the last order words to the words seen after them.
+type Chain struct {
+	order int
+	next  map[string][]string
+	// starts are the states that open a sentence.
+	starts [][]string
+	words  []string
+}
+
+// Train builds a chain over the words of texts. Each text is a separate
+// source, so no transition runs from the end of one into the next.
+func Train(texts []string, order int) (*Chain, error) {
+	if order < MinOrder || order > MaxOrder {
+		return nil, fmt.Errorf("%w: %d, want %d to %d", ErrInvalidOrder, order, MinOrder, MaxOrder)
+	}
+
+	chain := &Chain{order: order, next: map[string][]string{}}
+	for _, text := range texts {
+		words := strings.Fields(text)
+		chain.words = append(chain.words, words...)
+		for i := 0; i+order < len(words); i++ {
+			state := words[i : i+order]
+			if i == 0 || endsSentence(words[i-1]) {
+				chain.starts = append(chain.starts, state)
+			}
+			key := strings.Join(state, " ")
+			chain.next[key] = append(chain.next[key], words[i+order])
+		}
+	}
+
+	if len(chain.starts) == 0 {
+		return nil, ErrEmptyCorpus
+	}
+	return chain, nil
+}
+
+func endsSentence(word string) bool {
+	return strings.HasSuffix(word, ".") || strings.HasSuffix(word, "!") || strings.HasSuffix(word, "?")
+}
+
+// Generator draws words from a chain.
+type Generator struct {
+	chain *Chain
+	// coherence is the chance, from 0 to 1, that the next word follows
+	// the chain rather than being any word of the corpus.
+	coherence float64
+	// seed makes every test the same text when it is not zero.
+	seed int64
+}
+
+func NewGenerator(chain *Chain, coherence float64, seed int64) (*Generator, error) {
+	if coherence < 0 || coherence > 1 {
+		return nil, fmt.Errorf("%w: %.2f, want 0 to 1", ErrInvalidCoherence, coherence)
+	}
+	return &Generator{chain: chain, coherence: coherence, seed: seed}, nil
+}
+
+// Words generates n words. Dead ends, and the end of the corpus, start a
+// new sentence.
+func (g *Generator) Words(n int) []string {
+	seed := g.seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	words := make([]string, 0, n)
+	var state []string
+	for len(words) < n {
+		if len(state) < g.chain.order {
+			start := g.chain.starts[rng.Intn(len(g.chain.starts))]
+			state = append([]string(nil), start...)
+			words = append(words, start...)
+			continue
+		}
+
+		var word string
+		followers := g.chain.next[strings.Join(state, " ")]
+		switch {
+		case len(followers) == 0:
+			state = nil
+			continue
+		case rng.Float64() < g.coherence:
+			word = followers[rng.Intn(len(followers))]
+		default:
+			word = g.chain.words[rng.Intn(len(g.chain.words))]
+		}
+
+		words = append(words, word)
+		state = append(state[1:], word)
+	}
+	return words[:n]
+}
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrBelowMinWPM      = errors.New("below minimum wpm")
+	ErrBelowMinAccuracy = errors.New("below minimum accuracy")
+	ErrBelowMinBurst    = errors.New("below minimum burst")
+)
+
+// Minimums are pace thresholds that fail a running test. A zero value
+// turns its threshold off.
+type Minimums struct {
+	WPM      float64
+	Accuracy float64
+	// Burst is the speed of a single word, from the previous space to
+	// the space that submits it.
+	Burst float64
+	// Grace is how long into the test the thresholds wait before they
+	// apply, since the first seconds are too noisy to judge.
+	Grace time.Duration
+}
+
+func (m Minimums) Enabled() bool {
+	return m.WPM > 0 || m.Accuracy > 0 || m.Burst > 0
+}
+
+// CheckMinimums tests the running WPM and accuracy. It is called on every
+// tick and keystroke; bursts are checked as each word is submitted.
+func CheckMinimums(test *TypingTest) error {
+	if test == nil || test.Completed || test.StartTime.IsZero() || IsPaused(test) {
+		return nil
+	}
+	limits := test.Minimums
+	if ActiveDuration(test, time.Now()) < limits.Grace {
+		return nil
+	}
+
+	if limits.WPM > 0 {
+		if wpm := CalculateWPM(test); wpm < limits.WPM {
+			return fmt.Errorf("%w: %.0f of %.0f", ErrBelowMinWPM, wpm, limits.WPM)
+		}
+	}
+	if limits.Accuracy > 0 {
+		if accuracy := CalculateAccuracy(test); accuracy < limits.Accuracy {
+			return fmt.Errorf("%w: %.1f%% of %.0f%%", ErrBelowMinAccuracy, accuracy, limits.Accuracy)
+		}
+	}
+	return nil
+}
+
+// recordBurst measures the word just submitted at pos, the index of the
+// space or last character that ended it, and checks it against the
+// minimum burst. A wrong submitting key is not measured, but the next
+// word is still timed from it.
+func recordBurst(test *TypingTest, target []rune, pos int, offset time.Duration, correct bool) error {
+	chars := pos - wordStart(test, target, pos) + 1
+	elapsed := offset - test.burstStart
+	test.burstStart = offset
+	if !correct || elapsed <= 0 {
+		return nil
+	}
+
+	test.LastBurst = (float64(chars) / CharsPerWord) / elapsed.Minutes()
+	if test.Minimums.Burst > 0 && offset >= test.Minimums.Grace && test.LastBurst < test.Minimums.Burst {
+		return fmt.Errorf("%w: %.0f of %.0f", ErrBelowMinBurst, test.LastBurst, test.Minimums.Burst)
+	}
+	return nil
+}
@@ -8,6 +8,11 @@ const (
 	StateMenu GameState = iota
 	StateTyping
 	StateResults
+	StateThemes
+	StateFailed
+	StateFunbox
+	StateQuotes
+	StateLessonMap
 )
 
 type TypedChar struct {
@@ -16,6 +21,31 @@ type TypedChar struct {
 	Timestamp time.Time
 }
 
+type KeystrokeKind int
+
+const (
+	KeystrokeChar KeystrokeKind = iota
+	KeystrokeBackspace
+	KeystrokeWordDelete
+)
+
+// Keystroke is one entry of a test's input history. Unlike TypedChars it
+// keeps keys that were later deleted, and the deletions themselves.
+type Keystroke struct {
+	Kind      KeystrokeKind
+	Char      rune
+	Expected  rune
+	IsCorrect bool
+	Pasted    bool
+	// Rejected marks a key refused by stop on error. It was never typed
+	// but still counts against accuracy.
+	Rejected bool
+	// Deleted is how many characters a word delete removed.
+	Deleted int
+	// Offset is the time since the test started, with pauses left out.
+	Offset time.Duration
+}
+
 type WordStatus struct {
 	StartIndex int
 	EndIndex   int
@@ -27,12 +57,61 @@ type TypingTest struct {
 	Words        []string
 	TargetText   string
 	TypedChars   []TypedChar
+	Keystrokes   []Keystroke
 	CurrentPos   int
 	StartTime    time.Time
 	EndTime      time.Time
 	Duration     time.Duration
 	Completed    bool
 	WordStatuses []WordStatus
+	// PausedAt is when the current pause began, zero while running.
+	PausedAt       time.Time
+	PausedDuration time.Duration
+	Pauses         int
+	// FreedomMode lets backspace reopen words that were typed correctly.
+	FreedomMode bool
+	Difficulty  Difficulty
+	StopOnError StopOnError
+	// Rejected is the last key refused by stop on error, shown at the
+	// caret until the next key is accepted.
+	Rejected rune
+	// Failure is set when the test ended early; Completed is also true.
+	Failure  *Failure
+	Minimums Minimums
+	// LastBurst is the WPM of the last submitted word.
+	LastBurst  float64
+	burstStart time.Duration
+	// NoSpace tests run the words together with no spaces between them.
+	NoSpace  bool
+	Funboxes []string
+	Lenient  Lenient
+	// Quote is the quote the text came from, nil for random words.
+	Quote *QuoteRef
+	// Mode is where the text came from, and Exercise the curriculum
+	// lesson of an exercise.
+	Mode     Mode
+	Exercise string
+	// Pending holds keys typed towards a character that takes more than
+	// one key under lenient matching, like the first s of ß.
+	Pending []rune
+	// deadKey is the base key of an accented character, like the e of é,
+	// held for the combining mark that terminals passing dead keys through
+	// undecoded send after it.
+	deadKey rune
+}
+
+// QuoteRef names the quote a test was typed from.
+type QuoteRef struct {
+	ID       int
+	Language string
+	Source   string
+}
+
+type Failure struct {
+	Reason error
+	// Word is the target word where the test failed.
+	Word     string
+	Position int
 }
 
 type TestResult struct {
@@ -45,9 +124,42 @@ type TestResult struct {
 	ErrorCount   int
 	TestDuration time.Duration
 	CompletedAt  time.Time
+	// Pauses counts how often the test was paused. Paused time is already
+	// left out of TestDuration and WPM.
+	Pauses         int
+	PausedDuration time.Duration
+	RawWPM         float64
+	// Invalid gives the reason the result does not count, or is empty
+	// for a valid result.
+	Invalid string
+	// Failed gives the reason a test failed, and FailedWord the word it
+	// failed on. Both are empty for a passed test.
+	Failed     string
+	FailedWord string
+	Difficulty Difficulty
+	Funboxes   []string
+	Lenient    Lenient
+	Quote      *QuoteRef
+	Mode       Mode
+	Exercise   string
+	// KeyStats and MissedWords feed weakness practice.
+	KeyStats    KeyStats
+	MissedWords []string
 }
 
 type GameConfig struct {
 	TestDuration time.Duration
 	WordCount    int
+	FreedomMode  bool
+	Difficulty   Difficulty
+	StopOnError  StopOnError
+	Minimums     Minimums
+	NoSpace      bool
+	// Funboxes names the funboxes the test was built with, so results
+	// can record them.
+	Funboxes  []string
+	Lenient   Lenient
+	Selection WordSelection
+	// Mode is recorded on tests built from the config.
+	Mode Mode
 }
@@ -0,0 +1,232 @@
+// Package quote is the quote corpus behind quote mode. Quotes ship with
+// the binary, one collection per language, and each carries its source.
+package quote
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/BurntSushi/toml"
+)
+
+//go:embed data/*.toml
+var data embed.FS
+
+const DefaultLanguage = "english"
+
+var (
+	ErrUnknownLanguage = errors.New("unknown quote language")
+	ErrInvalidLength   = errors.New("invalid quote length")
+	ErrNotFound        = errors.New("quote not found")
+)
+
+// Length is a bucket of quote lengths, after monkeytype's.
+type Length int
+
+const (
+	LengthAll Length = iota
+	LengthShort
+	LengthMedium
+	LengthLong
+	LengthThicc
+)
+
+// Lengths lists the buckets in menu order.
+var Lengths = []Length{LengthAll, LengthShort, LengthMedium, LengthLong, LengthThicc}
+
+func (l Length) String() string {
+	switch l {
+	case LengthShort:
+		return "short"
+	case LengthMedium:
+		return "medium"
+	case LengthLong:
+		return "long"
+	case LengthThicc:
+		return "thicc"
+	}
+	return "all"
+}
+
+func ParseLength(value string) (Length, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "all":
+		return LengthAll, nil
+	case "short":
+		return LengthShort, nil
+	case "medium":
+		return LengthMedium, nil
+	case "long":
+		return LengthLong, nil
+	case "thicc":
+		return LengthThicc, nil
+	}
+	return LengthAll, fmt.Errorf("%w: %q", ErrInvalidLength, value)
+}
+
+type Quote struct {
+	ID       int    `toml:"id"`
+	Text     string `toml:"text"`
+	Source   string `toml:"source"`
+	Language string `toml:"-"`
+}
+
+// Key names the quote across languages, e.g. "english/12", for favorites
+// and ratings.
+func (q Quote) Key() string {
+	return q.Language + "/" + strconv.Itoa(q.ID)
+}
+
+// Length buckets the quote by its character count.
+func (q Quote) Length() Length {
+	switch n := utf8.RuneCountInString(q.Text); {
+	case n <= 100:
+		return LengthShort
+	case n <= 300:
+		return LengthMedium
+	case n <= 600:
+		return LengthLong
+	}
+	return LengthThicc
+}
+
+func (q Quote) Words() []string {
+	return strings.Fields(q.Text)
+}
+
+type collection struct {
+	Language string  `toml:"language"`
+	Quotes   []Quote `toml:"quote"`
+}
+
+// Corpus holds every collection, keyed by language.
+type Corpus struct {
+	collections map[string][]Quote
+}
+
+// Load parses the embedded collections.
+func Load() (*Corpus, error) {
+	files, err := fs.Glob(data, "data/*.toml")
+	if err != nil {
+		return nil, err
+	}
+
+	corpus := &Corpus{collections: map[string][]Quote{}}
+	var errs []error
+	for _, file := range files {
+		content, err := data.ReadFile(file)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var c collection
+		if _, err := toml.Decode(string(content), &c); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+			continue
+		}
+		for i := range c.Quotes {
+			c.Quotes[i].Language = c.Language
+		}
+		corpus.collections[c.Language] = c.Quotes
+	}
+	return corpus, errors.Join(errs...)
+}
+
+func (c *Corpus) Languages() []string {
+	languages := make([]string, 0, len(c.collections))
+	for language := range c.collections {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+func (c *Corpus) Quotes(language string) ([]Quote, error) {
+	quotes, ok := c.collections[language]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownLanguage, language)
+	}
+	return quotes, nil
+}
+
+// Lengths lists the buckets language has quotes in, in menu order,
+// always starting with LengthAll.
+func (c *Corpus) Lengths(language string) []Length {
+	has := map[Length]bool{LengthAll: true}
+	for _, q := range c.collections[language] {
+		has[q.Length()] = true
+	}
+	var lengths []Length
+	for _, length := range Lengths {
+		if has[length] {
+			lengths = append(lengths, length)
+		}
+	}
+	return lengths
+}
+
+func (c *Corpus) Find(language string, id int) (Quote, error) {
+	quotes, err := c.Quotes(language)
+	if err != nil {
+		return Quote{}, err
+	}
+	for _, q := range quotes {
+		if q.ID == id {
+			return q, nil
+		}
+	}
+	return Quote{}, fmt.Errorf("%w: %s #%d", ErrNotFound, language, id)
+}
+
+// Search matches query against the quotes of language. A number, with or
+// without a leading #, looks the quote up by ID; anything else matches
+// the text or source, ignoring case. An empty query lists every quote.
+func (c *Corpus) Search(language, query string) ([]Quote, error) {
+	quotes, err := c.Quotes(language)
+	if err != nil {
+		return nil, err
+	}
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return quotes, nil
+	}
+	if id, err := strconv.Atoi(strings.TrimPrefix(query, "#")); err == nil {
+		q, err := c.Find(language, id)
+		if err != nil {
+			return nil, nil
+		}
+		return []Quote{q}, nil
+	}
+
+	var found []Quote
+	for _, q := range quotes {
+		if strings.Contains(strings.ToLower(q.Text), query) ||
+			strings.Contains(strings.ToLower(q.Source), query) {
+			found = append(found, q)
+		}
+	}
+	return found, nil
+}
+
+// Pick draws a random quote of the given length from quotes. keep, when
+// not nil, narrows the choice further, e.g. to favorites.
+func Pick(quotes []Quote, length Length, keep func(Quote) bool, rng *rand.Rand) (Quote, bool) {
+	var pool []Quote
+	for _, q := range quotes {
+		if (length == LengthAll || q.Length() == length) && (keep == nil || keep(q)) {
+			pool = append(pool, q)
+		}
+	}
+	if len(pool) == 0 {
+		return Quote{}, false
+	}
+	return pool[rng.Intn(len(pool))], true
+}
@@ -19,18 +19,30 @@ func CountCorrectChars(test *TypingTest) int {
 	return correctChars
 }
 
-func CalculateWPM(test *TypingTest) float64 {
+// ActiveDuration is how long the test has been typed as of now, leaving
+// out every pause, including one still in progress.
+func ActiveDuration(test *TypingTest, now time.Time) time.Duration {
 	if test == nil || test.StartTime.IsZero() {
 		return 0
 	}
-
-	var duration time.Duration
 	if test.Completed {
-		duration = test.Duration
-	} else {
-		duration = time.Since(test.StartTime)
+		return test.Duration
+	}
+
+	paused := test.PausedDuration
+	if IsPaused(test) {
+		paused += now.Sub(test.PausedAt)
+	}
+	return now.Sub(test.StartTime) - paused
+}
+
+func CalculateWPM(test *TypingTest) float64 {
+	if test == nil || test.StartTime.IsZero() {
+		return 0
 	}
 
+	duration := ActiveDuration(test, time.Now())
+
 	if duration.Seconds() <= 0 {
 		return 0
 	}
@@ -41,11 +53,44 @@ func CalculateWPM(test *TypingTest) float64 {
 	return (float64(correctChars) / CharsPerWord) / minutes
 }
 
+func CountRejected(test *TypingTest) int {
+	rejected := 0
+	for _, keystroke := range test.Keystrokes {
+		if keystroke.Rejected {
+			rejected++
+		}
+	}
+	return rejected
+}
+
+// CalculateRawWPM counts every character keystroke, including mistakes
+// and characters deleted afterwards.
+func CalculateRawWPM(test *TypingTest) float64 {
+	if test == nil || test.StartTime.IsZero() {
+		return 0
+	}
+
+	duration := ActiveDuration(test, time.Now())
+	if duration.Seconds() <= 0 {
+		return 0
+	}
+
+	typed := 0
+	for _, keystroke := range test.Keystrokes {
+		if keystroke.Kind == KeystrokeChar {
+			typed++
+		}
+	}
+	return (float64(typed) / CharsPerWord) / duration.Minutes()
+}
+
+// CalculateAccuracy counts keys refused by stop on error as mistakes, on
+// top of the characters left in the text.
 func CalculateAccuracy(test *TypingTest) float64 {
 	if test == nil {
 		return 0
 	}
-	totalChars := len(test.TypedChars)
+	totalChars := len(test.TypedChars) + CountRejected(test)
 	if totalChars == 0 {
 		return 100.0
 	}
@@ -55,6 +100,28 @@ func CalculateAccuracy(test *TypingTest) float64 {
 	return (float64(correctChars) / float64(totalChars)) * 100.0
 }
 
+// MissedWords lists each word typed with at least one error, once.
+func MissedWords(test *TypingTest) []string {
+	if test == nil {
+		return nil
+	}
+
+	target := []rune(test.TargetText)
+	var missed []string
+	seen := make(map[string]bool)
+	for _, status := range test.WordStatuses {
+		word := string(target[status.StartIndex : status.EndIndex+1])
+		if !status.HasError || word == " " {
+			continue
+		}
+		if !seen[word] {
+			seen[word] = true
+			missed = append(missed, word)
+		}
+	}
+	return missed
+}
+
 func GenerateResult(test *TypingTest) *TestResult {
 	if test == nil {
 		return nil
@@ -62,35 +129,42 @@ func GenerateResult(test *TypingTest) *TestResult {
 	totalChars := len(test.TypedChars)
 	correctChars := CountCorrectChars(test)
 
+	// Count finished word units rather than typed spaces, so the last
+	// word counts and nospace tests work.
+	target := []rune(test.TargetText)
 	correctWords := 0
-	if len(test.TypedChars) > 0 {
-		wordStart := 0
-		for i, typedChar := range test.TypedChars {
-			if typedChar.Character == ' ' {
-				wordIsCorrect := true
-				for j := wordStart; j <= i; j++ {
-					if !test.TypedChars[j].IsCorrect {
-						wordIsCorrect = false
-						break
-					}
-				}
-				if wordIsCorrect {
-					correctWords++
-				}
-				wordStart = i + 1
-			}
+	for _, status := range test.WordStatuses {
+		isSpace := status.StartIndex == status.EndIndex && target[status.StartIndex] == ' '
+		if status.IsComplete && !status.HasError && !isSpace {
+			correctWords++
 		}
 	}
 
-	return &TestResult{
-		WPM:          CalculateWPM(test),
-		Accuracy:     CalculateAccuracy(test),
-		TotalWords:   len(test.Words),
-		CorrectWords: correctWords,
-		TotalChars:   totalChars,
-		CorrectChars: correctChars,
-		ErrorCount:   totalChars - correctChars,
-		TestDuration: test.Duration,
-		CompletedAt:  test.EndTime,
+	result := &TestResult{
+		WPM:            CalculateWPM(test),
+		RawWPM:         CalculateRawWPM(test),
+		Accuracy:       CalculateAccuracy(test),
+		TotalWords:     len(test.Words),
+		CorrectWords:   correctWords,
+		TotalChars:     totalChars,
+		CorrectChars:   correctChars,
+		ErrorCount:     totalChars - correctChars,
+		TestDuration:   test.Duration,
+		CompletedAt:    test.EndTime,
+		Pauses:         test.Pauses,
+		PausedDuration: test.PausedDuration,
+		Difficulty:     test.Difficulty,
+		Funboxes:       test.Funboxes,
+		Lenient:        test.Lenient,
+		Quote:          test.Quote,
+		Mode:           test.Mode,
+		Exercise:       test.Exercise,
+		KeyStats:       CollectKeyStats(test),
+		MissedWords:    MissedWords(test),
+	}
+	if test.Failure != nil {
+		result.Failed = test.Failure.Reason.Error()
+		result.FailedWord = test.Failure.Word
 	}
+	return result
 }
@@ -0,0 +1,105 @@
+package theme
+
+func Default() Theme {
+	return Theme{
+		Name:               DefaultName,
+		Bg:                 "#000000",
+		Main:               "#e2b714",
+		Caret:              "#ffffff",
+		Sub:                "#646669",
+		SubAlt:             "#2c2e31",
+		Text:               "#ffffff",
+		Error:              "#ff0000",
+		ErrorExtra:         "#4d0000",
+		ColorfulError:      "#ff0000",
+		ColorfulErrorExtra: "#4d0000",
+	}
+}
+
+func DefaultLight() Theme {
+	return Theme{
+		Name:               DefaultLightName,
+		Bg:                 "#ffffff",
+		Main:               "#b8860b",
+		Caret:              "#000000",
+		Sub:                "#8a8d91",
+		SubAlt:             "#e8e8e8",
+		Text:               "#1a1a1a",
+		Error:              "#d70000",
+		ErrorExtra:         "#ffd7d7",
+		ColorfulError:      "#d70000",
+		ColorfulErrorExtra: "#ffd7d7",
+	}
+}
+
+func Builtin() []Theme {
+	return []Theme{
+		Default(),
+		DefaultLight(),
+		{
+			Name: "serika_dark", Bg: "#323437", Main: "#e2b714", Caret: "#e2b714",
+			Sub: "#646669", SubAlt: "#2c2e31", Text: "#d1d0c5",
+			Error: "#ca4754", ErrorExtra: "#7e2a33", ColorfulError: "#ca4754", ColorfulErrorExtra: "#7e2a33",
+		},
+		{
+			Name: "serika", Bg: "#e1e1e3", Main: "#e2b714", Caret: "#e2b714",
+			Sub: "#aaaeb3", SubAlt: "#d1d3d8", Text: "#323437",
+			Error: "#da3333", ErrorExtra: "#791717", ColorfulError: "#da3333", ColorfulErrorExtra: "#791717",
+		},
+		{
+			Name: "dracula", Bg: "#282a36", Main: "#bd93f9", Caret: "#f8f8f2",
+			Sub: "#6272a4", SubAlt: "#20222c", Text: "#f8f8f2",
+			Error: "#ff5555", ErrorExtra: "#f1fa8c", ColorfulError: "#ff5555", ColorfulErrorExtra: "#f1fa8c",
+		},
+		{
+			Name: "nord", Bg: "#242933", Main: "#d8dee9", Caret: "#d8dee9",
+			Sub: "#617b94", SubAlt: "#1d2128", Text: "#d8dee9",
+			Error: "#bf616a", ErrorExtra: "#793e44", ColorfulError: "#bf616a", ColorfulErrorExtra: "#793e44",
+		},
+		{
+			Name: "gruvbox_dark", Bg: "#282828", Main: "#d79921", Caret: "#fabd2f",
+			Sub: "#665c54", SubAlt: "#1d2021", Text: "#ebdbb2",
+			Error: "#fb4934", ErrorExtra: "#cc241d", ColorfulError: "#cc241d", ColorfulErrorExtra: "#9d0006",
+		},
+		{
+			Name: "monokai", Bg: "#272822", Main: "#a6e22e", Caret: "#66d9ef",
+			Sub: "#e6db74", SubAlt: "#1f201b", Text: "#e2e2dc",
+			Error: "#f92672", ErrorExtra: "#fd971f", ColorfulError: "#f92672", ColorfulErrorExtra: "#fd971f",
+		},
+		{
+			Name: "carbon", Bg: "#313131", Main: "#f66e0d", Caret: "#f66e0d",
+			Sub: "#616161", SubAlt: "#2b2b2b", Text: "#f5e6c8",
+			Error: "#e72d2d", ErrorExtra: "#7e2a33", ColorfulError: "#e72d2d", ColorfulErrorExtra: "#7e2a33",
+		},
+		{
+			Name: "8008", Bg: "#333a45", Main: "#f44c7f", Caret: "#f44c7f",
+			Sub: "#939eae", SubAlt: "#2e343d", Text: "#e9ecf0",
+			Error: "#da3333", ErrorExtra: "#791717", ColorfulError: "#c5da33", ColorfulErrorExtra: "#849224",
+		},
+		{
+			Name: "botanical", Bg: "#7b9c98", Main: "#eaf1f3", Caret: "#abc6c4",
+			Sub: "#495755", SubAlt: "#6f8e8a", Text: "#eaf1f3",
+			Error: "#f6c9b4", ErrorExtra: "#f59a71", ColorfulError: "#f6c9b4", ColorfulErrorExtra: "#f59a71",
+		},
+		{
+			Name: "olivia", Bg: "#1c1b1d", Main: "#deaf9d", Caret: "#deaf9d",
+			Sub: "#4e3e3e", SubAlt: "#151416", Text: "#f2efed",
+			Error: "#bf616a", ErrorExtra: "#793e44", ColorfulError: "#e03d4e", ColorfulErrorExtra: "#aa2f3b",
+		},
+		{
+			Name: "bento", Bg: "#2d394d", Main: "#ff7a90", Caret: "#ff7a90",
+			Sub: "#4a768d", SubAlt: "#263041", Text: "#fffaf8",
+			Error: "#ee2a3a", ErrorExtra: "#f04040", ColorfulError: "#fc2032", ColorfulErrorExtra: "#f04040",
+		},
+		{
+			Name: "catppuccin", Bg: "#1e1e2e", Main: "#cba6f7", Caret: "#f5e0dc",
+			Sub: "#7f849c", SubAlt: "#181825", Text: "#cdd6f4",
+			Error: "#f38ba8", ErrorExtra: "#eba0ac", ColorfulError: "#f38ba8", ColorfulErrorExtra: "#eba0ac",
+		},
+		{
+			Name: "solarized_dark", Bg: "#002b36", Main: "#859900", Caret: "#dc322f",
+			Sub: "#2aa198", SubAlt: "#073642", Text: "#268bd2",
+			Error: "#d33682", ErrorExtra: "#9b225c", ColorfulError: "#d33682", ColorfulErrorExtra: "#9b225c",
+		},
+	}
+}
@@ -0,0 +1,95 @@
+package theme
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var ErrUnknownFormat = errors.New("unrecognized monkeytype theme format")
+
+// monkeytypeOrder is the order of colors in a monkeytype custom theme
+// export, which is a bare JSON array of ten hex strings.
+var monkeytypeOrder = []string{
+	"bg", "main", "caret", "sub", "sub-alt",
+	"text", "error", "error-extra", "colorful-error", "colorful-error-extra",
+}
+
+var cssVariable = regexp.MustCompile(`--([a-z-]+)-color\s*:\s*(#[0-9a-fA-F]{3,8})`)
+
+// ParseMonkeytype accepts either a monkeytype theme stylesheet (the
+// --bg-color, --main-color, ... CSS variables) or a custom theme export
+// (a JSON array of colors, optionally wrapped as {"c": [...]}).
+func ParseMonkeytype(name string, data []byte) (Theme, error) {
+	colors, err := parseMonkeytypeJSON(data)
+	if err != nil {
+		colors = parseMonkeytypeCSS(data)
+	}
+	if len(colors) == 0 {
+		return Theme{}, ErrUnknownFormat
+	}
+
+	t := Theme{
+		Name:               name,
+		Bg:                 colors["bg"],
+		Main:               colors["main"],
+		Caret:              colors["caret"],
+		Sub:                colors["sub"],
+		SubAlt:             colors["sub-alt"],
+		Text:               colors["text"],
+		Error:              colors["error"],
+		ErrorExtra:         colors["error-extra"],
+		ColorfulError:      colors["colorful-error"],
+		ColorfulErrorExtra: colors["colorful-error-extra"],
+	}
+	return t.Normalize()
+}
+
+func ImportMonkeytypeFile(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+	t, err := ParseMonkeytype(nameFromPath(path), data)
+	if err != nil {
+		return Theme{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+func parseMonkeytypeJSON(data []byte) (map[string]string, error) {
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		var wrapped struct {
+			C []string `json:"c"`
+		}
+		if err := json.Unmarshal(data, &wrapped); err != nil {
+			return nil, err
+		}
+		list = wrapped.C
+	}
+
+	if len(list) < len(monkeytypeOrder) {
+		return nil, fmt.Errorf("%w: expected %d colors, got %d", ErrUnknownFormat, len(monkeytypeOrder), len(list))
+	}
+
+	colors := make(map[string]string, len(monkeytypeOrder))
+	for i, key := range monkeytypeOrder {
+		colors[key] = list[i]
+	}
+	return colors, nil
+}
+
+func parseMonkeytypeCSS(data []byte) map[string]string {
+	colors := map[string]string{}
+	for _, match := range cssVariable.FindAllStringSubmatch(string(data), -1) {
+		key := strings.ToLower(match[1])
+		if _, seen := colors[key]; !seen {
+			colors[key] = match[2]
+		}
+	}
+	return colors
+}
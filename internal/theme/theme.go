@@ -0,0 +1,155 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrThemeNotFound = errors.New("theme not found")
+	ErrInvalidColor  = errors.New("invalid color")
+	ErrMissingName   = errors.New("theme name is missing")
+)
+
+const (
+	DefaultName      = "aiotype"
+	DefaultLightName = "aiotype_light"
+)
+
+type Theme struct {
+	Name               string `toml:"name"`
+	Bg                 string `toml:"bg"`
+	Main               string `toml:"main"`
+	Caret              string `toml:"caret"`
+	Sub                string `toml:"sub"`
+	SubAlt             string `toml:"sub_alt"`
+	Text               string `toml:"text"`
+	Error              string `toml:"error"`
+	ErrorExtra         string `toml:"error_extra"`
+	ColorfulError      string `toml:"colorful_error"`
+	ColorfulErrorExtra string `toml:"colorful_error_extra"`
+}
+
+// Normalize fills optional colors from their closest required counterpart
+// and expands shorthand hex values, so every field is a #rrggbb string.
+func (t Theme) Normalize() (Theme, error) {
+	if strings.TrimSpace(t.Name) == "" {
+		return t, ErrMissingName
+	}
+
+	if t.Caret == "" {
+		t.Caret = t.Main
+	}
+	if t.SubAlt == "" {
+		t.SubAlt = t.Bg
+	}
+	if t.ErrorExtra == "" {
+		t.ErrorExtra = t.Error
+	}
+	if t.ColorfulError == "" {
+		t.ColorfulError = t.Error
+	}
+	if t.ColorfulErrorExtra == "" {
+		t.ColorfulErrorExtra = t.ErrorExtra
+	}
+
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"bg", &t.Bg},
+		{"main", &t.Main},
+		{"caret", &t.Caret},
+		{"sub", &t.Sub},
+		{"sub_alt", &t.SubAlt},
+		{"text", &t.Text},
+		{"error", &t.Error},
+		{"error_extra", &t.ErrorExtra},
+		{"colorful_error", &t.ColorfulError},
+		{"colorful_error_extra", &t.ColorfulErrorExtra},
+	}
+	for _, field := range fields {
+		hex, err := NormalizeHex(*field.value)
+		if err != nil {
+			return t, fmt.Errorf("%s: %s: %w", t.Name, field.name, err)
+		}
+		*field.value = hex
+	}
+
+	return t, nil
+}
+
+func NormalizeHex(value string) (string, error) {
+	hex := strings.ToLower(strings.TrimSpace(value))
+	if !strings.HasPrefix(hex, "#") {
+		return "", fmt.Errorf("%w %q", ErrInvalidColor, value)
+	}
+	digits := hex[1:]
+
+	switch len(digits) {
+	case 3:
+		digits = string([]byte{digits[0], digits[0], digits[1], digits[1], digits[2], digits[2]})
+	case 6:
+	case 8:
+		// #rrggbbaa: the terminal has no alpha channel, so drop it.
+		digits = digits[:6]
+	default:
+		return "", fmt.Errorf("%w %q", ErrInvalidColor, value)
+	}
+
+	for _, c := range digits {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return "", fmt.Errorf("%w %q", ErrInvalidColor, value)
+		}
+	}
+
+	return "#" + digits, nil
+}
+
+type Registry struct {
+	themes []Theme
+}
+
+func NewRegistry() *Registry {
+	r := &Registry{}
+	for _, t := range Builtin() {
+		r.Add(t)
+	}
+	return r
+}
+
+// Add registers a theme, replacing any existing theme with the same name.
+func (r *Registry) Add(t Theme) {
+	for i, existing := range r.themes {
+		if strings.EqualFold(existing.Name, t.Name) {
+			r.themes[i] = t
+			return
+		}
+	}
+	r.themes = append(r.themes, t)
+}
+
+func (r *Registry) Get(name string) (Theme, error) {
+	for _, t := range r.themes {
+		if strings.EqualFold(t.Name, name) {
+			return t, nil
+		}
+	}
+	return Theme{}, fmt.Errorf("%w: %s", ErrThemeNotFound, name)
+}
+
+func (r *Registry) All() []Theme {
+	themes := make([]Theme, len(r.themes))
+	copy(themes, r.themes)
+	return themes
+}
+
+func (r *Registry) Index(name string) int {
+	for i, t := range r.themes {
+		if strings.EqualFold(t.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
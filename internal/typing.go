@@ -2,9 +2,14 @@ package internal
 
 import (
 	"errors"
+	"fmt"
 	"math/rand"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 var (
@@ -12,8 +17,123 @@ var (
 	ErrTestCompleted       = errors.New("test is already completed")
 	ErrInvalidConfig       = errors.New("invalid configuration")
 	ErrPositionOutOfBounds = errors.New("position out of bounds")
+	ErrInvalidDifficulty   = errors.New("invalid difficulty")
+	ErrInvalidStopOnError  = errors.New("invalid stop on error mode")
+	ErrInvalidMode         = errors.New("invalid mode")
+	ErrWordSubmitted       = errors.New("submitted a word with an error")
+	ErrWrongKeystroke      = errors.New("typed an incorrect character")
+)
+
+// StopOnError keeps the caret from moving on past a mistake.
+type StopOnError int
+
+const (
+	StopOff StopOnError = iota
+	// StopLetter refuses wrong keys, so the caret only advances on the
+	// right one.
+	StopLetter
+	// StopWord refuses space, and the last key of the text, until the
+	// word is typed correctly.
+	StopWord
+)
+
+func ParseStopOnError(value string) (StopOnError, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "off":
+		return StopOff, nil
+	case "letter":
+		return StopLetter, nil
+	case "word":
+		return StopWord, nil
+	}
+	return StopOff, fmt.Errorf("%w: %q", ErrInvalidStopOnError, value)
+}
+
+// Difficulty decides which mistakes end a test early.
+type Difficulty int
+
+const (
+	DifficultyNormal Difficulty = iota
+	// DifficultyExpert fails the test when a word with an error is
+	// submitted with space, or ends the test.
+	DifficultyExpert
+	// DifficultyMaster fails the test on any incorrect keystroke.
+	DifficultyMaster
 )
 
+func (d Difficulty) String() string {
+	switch d {
+	case DifficultyExpert:
+		return "expert"
+	case DifficultyMaster:
+		return "master"
+	}
+	return "normal"
+}
+
+func ParseDifficulty(value string) (Difficulty, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "normal":
+		return DifficultyNormal, nil
+	case "expert":
+		return DifficultyExpert, nil
+	case "master":
+		return DifficultyMaster, nil
+	}
+	return DifficultyNormal, fmt.Errorf("%w: %q", ErrInvalidDifficulty, value)
+}
+
+// Mode picks where the text of a test comes from.
+type Mode int
+
+const (
+	// ModeWords types random common words.
+	ModeWords Mode = iota
+	// ModeQuote types one quote from the quote corpus.
+	ModeQuote
+	// ModeWeakness types words weighted towards the keys, pairs of keys
+	// and words the typist's history shows they struggle with.
+	ModeWeakness
+	// ModeLesson types pseudo-words made of the letters unlocked so far.
+	ModeLesson
+	// ModePractice drills the words missed in the last test. It is never
+	// configured, only started from the results.
+	ModePractice
+	// ModeExercise types an exercise of a curriculum lesson, started from
+	// the lesson map.
+	ModeExercise
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeQuote:
+		return "quote"
+	case ModeWeakness:
+		return "weakness"
+	case ModeLesson:
+		return "lesson"
+	case ModePractice:
+		return "practice"
+	case ModeExercise:
+		return "exercise"
+	}
+	return "words"
+}
+
+func ParseMode(value string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "words":
+		return ModeWords, nil
+	case "quote":
+		return ModeQuote, nil
+	case "weakness":
+		return ModeWeakness, nil
+	case "lesson":
+		return ModeLesson, nil
+	}
+	return ModeWords, fmt.Errorf("%w: %q", ErrInvalidMode, value)
+}
+
 func DefaultGameConfig() GameConfig {
 	return GameConfig{
 		TestDuration: 10 * time.Second,
@@ -21,42 +141,6 @@ func DefaultGameConfig() GameConfig {
 	}
 }
 
-var commonWords = []string{
-	"the", "be", "to", "of", "and", "a", "in", "that", "have", "i",
-	"it", "for", "not", "on", "with", "he", "as", "you", "do", "at",
-	"this", "but", "his", "by", "from", "they", "we", "say", "her", "she",
-	"or", "an", "will", "my", "one", "all", "would", "there", "their", "what",
-	"so", "up", "out", "if", "about", "who", "get", "which", "go", "me",
-	"when", "make", "can", "like", "time", "no", "just", "him", "know", "take",
-	"people", "into", "year", "your", "good", "some", "could", "them", "see", "other",
-	"than", "then", "now", "look", "only", "come", "its", "over", "think", "also",
-	"back", "use", "two", "how", "our", "work", "first", "well", "way",
-	"even", "new", "want", "because", "these", "give", "day", "most", "us",
-	"is", "water", "long", "very", "still", "through", "down",
-	"may", "such", "here", "were", "been", "much",
-	"where", "too", "each", "many",
-	"has", "more", "life", "should",
-	"being", "made", "before", "might", "did", "every", "large", "often",
-	"together", "asked", "house", "don't", "world", "going", "school", "important", "until", "form",
-	"food", "keep", "children", "feet", "land", "side", "without", "boy", "once", "animal",
-	"enough", "took", "sometimes", "four", "head", "above", "kind", "began", "almost",
-	"live", "page", "got", "earth", "need", "far", "hand", "high", "mother",
-	"light", "country", "father", "let", "night", "picture", "study", "second", "soon",
-	"story", "since", "white", "ever", "paper", "hard", "near", "sentence", "better", "best",
-	"across", "during", "today", "however", "sure", "knew", "it's", "try", "told", "young",
-	"sun", "thing", "whole", "hear", "example", "heard", "several", "change", "answer", "room",
-	"sea", "against", "top", "turned", "learn", "point", "city", "play", "toward", "five",
-	"himself", "usually", "money", "seen", "didn't", "car", "morning", "i'm", "body", "upon",
-	"family", "later", "turn", "move", "face", "door", "cut", "done", "group", "true",
-	"leave", "color", "red", "friend", "pretty", "eat", "front", "feel", "fact",
-	"week", "eye", "same", "another", "left", "call", "while", "right",
-	"find", "part", "place", "under", "name", "help", "low", "line", "cause", "mean",
-	"differ", "old", "tell", "follow", "around", "three", "small", "set", "put", "end",
-	"why", "again", "off", "went", "number", "men", "found", "between", "home",
-	"big", "air", "own", "read", "last", "along", "next", "below", "saw",
-	"something", "thought", "both", "few", "those", "always", "looked",
-}
-
 func NewTest(config GameConfig) *TypingTest {
 	if config.WordCount <= 0 || config.TestDuration <= 0 {
 		return nil
@@ -64,25 +148,56 @@ func NewTest(config GameConfig) *TypingTest {
 
 	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 
-	words := make([]string, config.WordCount)
-	for i := 0; i < config.WordCount; i++ {
-		words[i] = commonWords[rng.Intn(len(commonWords))]
+	words := selectWords(randomPool(config.Selection), config.Selection, config.WordCount, rng)
+	return NewTestWithWords(config, words)
+}
+
+// NewPracticeTest builds a test of config.WordCount words drawn from words,
+// for drilling the words a typist got wrong.
+func NewPracticeTest(config GameConfig, words []string) *TypingTest {
+	if config.WordCount <= 0 || len(words) == 0 {
+		return nil
 	}
 
-	targetText := strings.Join(words, " ")
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	// The words are not ranked by frequency, so only the repeat window
+	// applies.
+	selection := WordSelection{Window: config.Selection.Window}
+	practice := selectWords(words, selection, config.WordCount, rng)
+	return NewTestWithWords(config, practice)
+}
+
+// NewTestWithWords builds a fresh test over a fixed word list, which is how
+// a test is repeated with the same words.
+func NewTestWithWords(config GameConfig, words []string) *TypingTest {
+	if len(words) == 0 || config.TestDuration <= 0 {
+		return nil
+	}
+	words = append([]string(nil), words...)
+	for i, word := range words {
+		words[i] = norm.NFC.String(word)
+	}
+
+	separator := " "
+	if config.NoSpace {
+		separator = ""
+	}
+	targetText := strings.Join(words, separator)
 
 	wordStatuses := []WordStatus{}
 	currentIndex := 0
 	for i, word := range words {
+		length := utf8.RuneCountInString(word)
 		wordStatuses = append(wordStatuses, WordStatus{
 			StartIndex: currentIndex,
-			EndIndex:   currentIndex + len(word) - 1,
+			EndIndex:   currentIndex + length - 1,
 			HasError:   false,
 			IsComplete: false,
 		})
-		currentIndex += len(word)
+		currentIndex += length
 
-		if i < len(words)-1 {
+		if i < len(words)-1 && !config.NoSpace {
 			wordStatuses = append(wordStatuses, WordStatus{
 				StartIndex: currentIndex,
 				EndIndex:   currentIndex,
@@ -100,38 +215,232 @@ func NewTest(config GameConfig) *TypingTest {
 		CurrentPos:   0,
 		Completed:    false,
 		WordStatuses: wordStatuses,
+		FreedomMode:  config.FreedomMode,
+		Difficulty:   config.Difficulty,
+		StopOnError:  config.StopOnError,
+		Minimums:     config.Minimums,
+		NoSpace:      config.NoSpace,
+		Funboxes:     config.Funboxes,
+		Lenient:      config.Lenient,
+		Mode:         config.Mode,
 	}
 }
 
+// ProcessCharacter types one character. Positions count runes, so
+// accented text lines up with what is shown.
 func ProcessCharacter(test *TypingTest, char rune) bool {
-	if test == nil || test.Completed || test.CurrentPos >= len(test.TargetText) {
+	return processCharacter(test, char, false)
+}
+
+// ProcessPaste types pasted text, marking every keystroke as pasted so the
+// result can be flagged.
+func ProcessPaste(test *TypingTest, text string) bool {
+	completed := false
+	for _, char := range norm.NFC.String(text) {
+		if char == '\n' || char == '\r' || char == '\t' {
+			char = ' '
+		}
+		completed = processCharacter(test, char, true)
+	}
+	return completed
+}
+
+// ComposeCharacter folds a combining mark, as sent by terminals that pass
+// dead keys through undecoded, into the key before it. A held dead key is
+// typed together with the mark, or followed by the mark as a key of its
+// own when the two do not combine. Otherwise the mark changes the last
+// typed character, as long as that was the last key and it was accepted,
+// and is dropped when there is nothing to combine with. Like
+// ProcessCharacter it reports whether the test ended.
+func ComposeCharacter(test *TypingTest, mark rune) bool {
+	if test == nil || test.Completed {
+		return false
+	}
+
+	if base := test.deadKey; base != 0 {
+		test.deadKey = 0
+		composed := []rune(norm.NFC.String(string(base) + string(mark)))
+		if len(composed) != 1 {
+			if typeCharacter(test, base, false, false) {
+				return true
+			}
+			return typeCharacter(test, mark, false, false)
+		}
+		return processCharacter(test, composed[0], false)
+	}
+
+	if len(test.TypedChars) == 0 || len(test.Keystrokes) == 0 || test.CurrentPos != len(test.TypedChars) {
+		return false
+	}
+	stroke := &test.Keystrokes[len(test.Keystrokes)-1]
+	if stroke.Kind != KeystrokeChar || stroke.Rejected {
+		return false
+	}
+
+	last := &test.TypedChars[len(test.TypedChars)-1]
+	composed := []rune(norm.NFC.String(string(last.Character) + string(mark)))
+	if len(composed) != 1 {
+		return false
+	}
+
+	target := []rune(test.TargetText)
+	expected := target[test.CurrentPos-1]
+	last.IsCorrect = test.Lenient.Matches(composed[0], expected)
+	last.Character = composed[0]
+	if last.IsCorrect {
+		last.Character = expected
+	}
+	stroke.Char = composed[0]
+	stroke.IsCorrect = last.IsCorrect
+
+	updateWordStatus(test)
+	if reason := difficultyFailure(test, target, composed[0], last.IsCorrect); reason != nil {
+		FailTest(test, reason, test.CurrentPos-1)
+		return true
+	}
+	return false
+}
+
+// composesFrom reports whether expected is char with combining marks,
+// like é from e, so char may be a dead key waiting for its mark.
+func composesFrom(char, expected rune) bool {
+	decomposed := []rune(norm.NFD.String(string(expected)))
+	if len(decomposed) < 2 || decomposed[0] != char {
+		return false
+	}
+	for _, mark := range decomposed[1:] {
+		if !IsCombiningMark(mark) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsCombiningMark reports whether r only modifies the character before it.
+func IsCombiningMark(r rune) bool {
+	return unicode.Is(unicode.Mn, r)
+}
+
+func processCharacter(test *TypingTest, char rune, pasted bool) bool {
+	if test == nil || test.Completed {
+		return false
+	}
+	// A held dead key with no mark after it was just a key.
+	if base := test.deadKey; base != 0 {
+		test.deadKey = 0
+		if typeCharacter(test, base, false, false) {
+			return true
+		}
+	}
+	return typeCharacter(test, char, pasted, !pasted)
+}
+
+// typeCharacter types one key. hold lets it keep back the base key of an
+// accented character for the mark that may follow.
+func typeCharacter(test *TypingTest, char rune, pasted, hold bool) bool {
+	target := []rune(test.TargetText)
+	if test.CurrentPos >= len(target) {
 		return false
 	}
 
 	if test.StartTime.IsZero() {
 		test.StartTime = time.Now()
 	}
+	ResumeTest(test)
 
-	if test.CurrentPos < 0 || test.CurrentPos >= len(test.TargetText) {
+	if test.CurrentPos < 0 {
 		return false
 	}
 
-	expected := rune(test.TargetText[test.CurrentPos])
-	isCorrect := char == expected
+	expected := target[test.CurrentPos]
+	isCorrect := test.Lenient.Matches(char, expected)
+
+	now := time.Now()
+	if keys := test.Lenient.sequence(expected); keys != nil && !isCorrect {
+		next := len(test.Pending)
+		if test.Lenient.Matches(char, keys[next]) {
+			if next+1 < len(keys) {
+				test.Pending = append(test.Pending, char)
+				test.Keystrokes = append(test.Keystrokes, Keystroke{
+					Kind:      KeystrokeChar,
+					Char:      char,
+					Expected:  expected,
+					IsCorrect: true,
+					Pasted:    pasted,
+					Offset:    ActiveDuration(test, now),
+				})
+				return false
+			}
+			isCorrect = true
+		}
+	}
+	test.Pending = nil
+
+	// Hold the base key of an accented character for its mark, so neither
+	// difficulty nor stop on error judge half of the character.
+	if !isCorrect && hold && composesFrom(char, expected) {
+		test.deadKey = char
+		return false
+	}
+
+	if test.Difficulty != DifficultyMaster && rejects(test, target, char, expected, isCorrect) {
+		test.Keystrokes = append(test.Keystrokes, Keystroke{
+			Kind:     KeystrokeChar,
+			Char:     char,
+			Expected: expected,
+			Rejected: true,
+			Pasted:   pasted,
+			Offset:   ActiveDuration(test, now),
+		})
+		test.Rejected = char
+		return false
+	}
+	test.Rejected = 0
 
+	// A lenient match shows the text as written rather than the key.
+	typed := char
+	if isCorrect {
+		typed = expected
+	}
 	test.TypedChars = append(test.TypedChars, TypedChar{
-		Character: char,
+		Character: typed,
+		IsCorrect: isCorrect,
+		Timestamp: now,
+	})
+	test.Keystrokes = append(test.Keystrokes, Keystroke{
+		Kind:      KeystrokeChar,
+		Char:      char,
+		Expected:  expected,
 		IsCorrect: isCorrect,
-		Timestamp: time.Now(),
+		Pasted:    pasted,
+		Offset:    ActiveDuration(test, now),
 	})
 	test.CurrentPos++
 
 	updateWordStatus(test)
 
-	if test.CurrentPos >= len(test.TargetText) {
+	if reason := difficultyFailure(test, target, char, isCorrect); reason != nil {
+		FailTest(test, reason, test.CurrentPos-1)
+		return true
+	}
+
+	if submits(test, target, test.CurrentPos-1) {
+		if reason := recordBurst(test, target, test.CurrentPos-1, ActiveDuration(test, now), isCorrect); reason != nil {
+			FailTest(test, reason, test.CurrentPos-1)
+			return true
+		}
+	}
+	if test.CurrentPos < len(target) {
+		if reason := CheckMinimums(test); reason != nil {
+			FailTest(test, reason, test.CurrentPos-1)
+			return true
+		}
+	}
+
+	if test.CurrentPos >= len(target) {
 		test.Completed = true
 		test.EndTime = time.Now()
-		test.Duration = test.EndTime.Sub(test.StartTime)
+		test.Duration = test.EndTime.Sub(test.StartTime) - test.PausedDuration
 		return true
 	}
 
@@ -139,12 +448,34 @@ func ProcessCharacter(test *TypingTest, char rune) bool {
 }
 
 func ProcessBackspace(test *TypingTest) {
-	if test == nil || len(test.TypedChars) == 0 {
+	if test != nil && test.deadKey != 0 {
+		test.deadKey = 0
 		return
 	}
+	if test != nil && len(test.Pending) > 0 {
+		ResumeTest(test)
+		test.Pending = test.Pending[:len(test.Pending)-1]
+		test.Keystrokes = append(test.Keystrokes, Keystroke{
+			Kind:   KeystrokeBackspace,
+			Offset: ActiveDuration(test, time.Now()),
+		})
+		return
+	}
+	if test == nil || len(test.TypedChars) == 0 || !canDelete(test, test.CurrentPos-1) {
+		return
+	}
+	ResumeTest(test)
+	test.Rejected = 0
 
 	prevPos := test.CurrentPos
 
+	offset := ActiveDuration(test, time.Now())
+	test.Keystrokes = append(test.Keystrokes, Keystroke{
+		Kind:   KeystrokeBackspace,
+		Offset: offset,
+	})
+	// The corrected word is timed from the correction.
+	test.burstStart = offset
 	test.TypedChars = test.TypedChars[:len(test.TypedChars)-1]
 	test.CurrentPos = len(test.TypedChars)
 
@@ -155,6 +486,204 @@ func ProcessBackspace(test *TypingTest) {
 	updateWordStatusOnBackspace(test, prevPos)
 }
 
+// rejects applies the stop on error mode to a key before it is typed.
+func rejects(test *TypingTest, target []rune, char, expected rune, isCorrect bool) bool {
+	switch test.StopOnError {
+	case StopLetter:
+		return !isCorrect
+	case StopWord:
+		if char == ' ' && expected != ' ' {
+			return true
+		}
+		if !submits(test, target, test.CurrentPos) {
+			return false
+		}
+		if expected != ' ' && !isCorrect {
+			return true
+		}
+		for i := wordStart(test, target, test.CurrentPos); i < test.CurrentPos; i++ {
+			if !test.TypedChars[i].IsCorrect {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// difficultyFailure checks the keystroke just typed against the test's
+// difficulty.
+func difficultyFailure(test *TypingTest, target []rune, char rune, isCorrect bool) error {
+	switch test.Difficulty {
+	case DifficultyMaster:
+		if !isCorrect {
+			return ErrWrongKeystroke
+		}
+	case DifficultyExpert:
+		if char != ' ' && !submits(test, target, test.CurrentPos-1) {
+			return nil
+		}
+		for i := wordStart(test, target, test.CurrentPos-1); i < test.CurrentPos; i++ {
+			if !test.TypedChars[i].IsCorrect {
+				return ErrWordSubmitted
+			}
+		}
+	}
+	return nil
+}
+
+// wordUnit is the index in WordStatuses of the word at pos. For a space
+// it is the word before the space.
+func wordUnit(test *TypingTest, target []rune, pos int) int {
+	unitIndex := GetWordIndexForPosition(test, pos)
+	if unitIndex > 0 && target[pos] == ' ' {
+		unitIndex--
+	}
+	return unitIndex
+}
+
+func wordStart(test *TypingTest, target []rune, pos int) int {
+	unitIndex := wordUnit(test, target, pos)
+	if unitIndex == -1 {
+		return pos
+	}
+	return test.WordStatuses[unitIndex].StartIndex
+}
+
+// submits reports whether typing pos hands in a word: a space, the end
+// of the text, or without spaces the last letter of a word.
+func submits(test *TypingTest, target []rune, pos int) bool {
+	if pos >= len(target)-1 || target[pos] == ' ' {
+		return true
+	}
+	if !test.NoSpace {
+		return false
+	}
+	unitIndex := GetWordIndexForPosition(test, pos)
+	return unitIndex != -1 && test.WordStatuses[unitIndex].EndIndex == pos
+}
+
+// FailTest ends a test early. pos is the target position where the
+// failure happened, and picks the word shown on the failed screen.
+func FailTest(test *TypingTest, reason error, pos int) {
+	if test == nil || test.Completed {
+		return
+	}
+	ResumeTest(test)
+
+	target := []rune(test.TargetText)
+	if pos >= len(target) {
+		pos = len(target) - 1
+	}
+	if pos < 0 {
+		pos = 0
+	}
+	word := ""
+	if unitIndex := wordUnit(test, target, pos); unitIndex != -1 {
+		unit := test.WordStatuses[unitIndex]
+		word = string(target[unit.StartIndex : unit.EndIndex+1])
+	}
+
+	test.Failure = &Failure{
+		Reason:   reason,
+		Word:     word,
+		Position: pos,
+	}
+	if test.StartTime.IsZero() {
+		test.StartTime = time.Now()
+	}
+	test.Completed = true
+	test.EndTime = time.Now()
+	test.Duration = test.EndTime.Sub(test.StartTime) - test.PausedDuration
+}
+
+// ProcessWordBackspace deletes back to the start of the current word, or
+// over the space and the whole previous word when the caret is right
+// after a space. It is logged as a single keystroke.
+func ProcessWordBackspace(test *TypingTest) {
+	if test == nil || test.Completed {
+		return
+	}
+	test.Pending = nil
+	test.deadKey = 0
+	if len(test.TypedChars) == 0 {
+		return
+	}
+
+	target := []rune(test.TargetText)
+	pos := test.CurrentPos
+	for pos > 0 && target[pos-1] == ' ' && canDelete(test, pos-1) {
+		pos--
+	}
+	if pos > 0 && target[pos-1] != ' ' && canDelete(test, pos-1) {
+		pos = wordStart(test, target, pos-1)
+	}
+	if pos == test.CurrentPos {
+		return
+	}
+	ResumeTest(test)
+	test.Rejected = 0
+
+	offset := ActiveDuration(test, time.Now())
+	test.Keystrokes = append(test.Keystrokes, Keystroke{
+		Kind:    KeystrokeWordDelete,
+		Deleted: test.CurrentPos - pos,
+		Offset:  offset,
+	})
+	test.burstStart = offset
+	test.TypedChars = test.TypedChars[:pos]
+	test.CurrentPos = pos
+
+	for i := range test.WordStatuses {
+		if test.WordStatuses[i].EndIndex >= pos {
+			test.WordStatuses[i].IsComplete = false
+			test.WordStatuses[i].HasError = false
+		}
+	}
+}
+
+// canDelete reports whether the typed character at pos may be removed.
+// Outside freedom mode the correct key that submitted a correctly typed
+// word, a space or without spaces its last letter, is locked, so a
+// finished word cannot be reopened.
+func canDelete(test *TypingTest, pos int) bool {
+	if test.FreedomMode || pos < 0 || pos >= len(test.TypedChars) || !test.TypedChars[pos].IsCorrect {
+		return true
+	}
+	target := []rune(test.TargetText)
+	if !submits(test, target, pos) {
+		return true
+	}
+	unitIndex := wordUnit(test, target, pos)
+	if unitIndex == -1 {
+		return true
+	}
+	word := test.WordStatuses[unitIndex]
+	return !word.IsComplete || word.HasError
+}
+
+// PauseTest stops the clock of a running test. A test that has not
+// started, has finished or is already paused is left alone.
+func PauseTest(test *TypingTest) {
+	if test == nil || test.StartTime.IsZero() || test.Completed || IsPaused(test) {
+		return
+	}
+	test.PausedAt = time.Now()
+	test.Pauses++
+}
+
+// ResumeTest restarts the clock, adding the pause to PausedDuration.
+func ResumeTest(test *TypingTest) {
+	if !IsPaused(test) {
+		return
+	}
+	test.PausedDuration += time.Since(test.PausedAt)
+	test.PausedAt = time.Time{}
+}
+
+func IsPaused(test *TypingTest) bool {
+	return test != nil && !test.PausedAt.IsZero()
+}
+
 func GetWordIndexForPosition(test *TypingTest, position int) int {
 	for i, ws := range test.WordStatuses {
 		if position >= ws.StartIndex && position <= ws.EndIndex {
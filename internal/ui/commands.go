@@ -0,0 +1,379 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+
+	"aiotype/internal"
+	"aiotype/internal/funbox"
+	"aiotype/internal/quote"
+	"aiotype/internal/ui/palette"
+	"aiotype/internal/ui/shared"
+)
+
+// commands lists everything the command palette offers. It is rebuilt on
+// every open, so settings mark their current value and actions that need
+// a finished test only appear after one.
+func (m *Model) commands() []palette.Command {
+	commands := m.actionCommands()
+	commands = append(commands, m.themeCommands()...)
+	commands = append(commands, m.settingCommands()...)
+	return commands
+}
+
+func (m *Model) actionCommands() []palette.Command {
+	commands := []palette.Command{
+		{Title: "Restart test", Run: m.startTest},
+		{Title: "Repeat test", Run: m.repeatTest},
+	}
+	if len(m.missedWords) > 0 {
+		commands = append(commands, palette.Command{Title: "Practice missed words", Run: m.practiceMissedWords})
+	}
+	if m.lastResult != nil {
+		commands = append(commands, palette.Command{Title: "Copy result", Run: m.copyResult})
+	}
+	return append(commands,
+		palette.Command{Title: "Search quotes", Run: m.openQuotes},
+		palette.Command{Title: "Lesson map", Run: m.openLessonMap},
+		palette.Command{Title: "Themes", Run: func() tea.Cmd {
+			m.themesModel.Open()
+			m.state = internal.StateThemes
+			return nil
+		}},
+		palette.Command{Title: "Menu", Run: func() tea.Cmd {
+			m.state = internal.StateMenu
+			return nil
+		}},
+		palette.Command{Title: "Quit", Run: func() tea.Cmd {
+			return tea.Quit
+		}},
+	)
+}
+
+func (m *Model) themeCommands() []palette.Command {
+	active := shared.Theme().Name
+	all := m.registry.All()
+
+	commands := make([]palette.Command, 0, len(all))
+	for _, t := range all {
+		commands = append(commands, palette.Command{
+			Group:  "Theme",
+			Title:  t.Name,
+			Active: t.Name == active,
+			Run: func() tea.Cmd {
+				shared.SetTheme(t)
+				m.settings.Theme = t.Name
+				if m.state == internal.StateThemes {
+					m.state = internal.StateMenu
+				}
+				return saveSettings(m.settings)
+			},
+		})
+	}
+	return commands
+}
+
+func (m *Model) settingCommands() []palette.Command {
+	s := &m.settings
+	var commands []palette.Command
+
+	for _, mode := range []string{"words", "quote", "weakness", "lesson"} {
+		commands = append(commands, m.setting("Mode", mode, s.Mode == mode, func() {
+			s.Mode = mode
+		}))
+	}
+	for _, kind := range []string{"random", "markov"} {
+		commands = append(commands, m.setting("Text", kind, s.Generator.Kind == kind, func() {
+			s.Generator.Kind = kind
+		}))
+	}
+	for _, topN := range []int{0, 100, 200, 500, 1000} {
+		title := "common"
+		if topN > 0 {
+			title = fmt.Sprintf("top %d", topN)
+		}
+		commands = append(commands, m.setting("Word pool", title, s.Generator.TopN == topN, func() {
+			s.Generator.TopN = topN
+		}))
+	}
+	for _, zipf := range []float64{0, 0.5, 1} {
+		title := "uniform"
+		if zipf > 0 {
+			title = fmt.Sprintf("zipf %.1f", zipf)
+		}
+		commands = append(commands, m.setting("Word weighting", title, s.Generator.Zipf == zipf, func() {
+			s.Generator.Zipf = zipf
+		}))
+	}
+	for _, window := range []int{0, 1, 3, 10} {
+		title := "off"
+		if window > 0 {
+			title = fmt.Sprintf("%d words", window)
+		}
+		commands = append(commands, m.setting("Repeat window", title, s.Generator.RepeatWindow == window, func() {
+			s.Generator.RepeatWindow = window
+		}))
+	}
+	for _, order := range []int{1, 2, 3} {
+		commands = append(commands, m.setting("Markov order", strconv.Itoa(order), s.Generator.Order == order, func() {
+			s.Generator.Order = order
+		}))
+	}
+	for _, coherence := range []float64{0.5, 0.75, 0.9, 1} {
+		title := fmt.Sprintf("%.0f%%", coherence*100)
+		commands = append(commands, m.setting("Coherence", title, s.Generator.Coherence == coherence, func() {
+			s.Generator.Coherence = coherence
+		}))
+	}
+	// Only lengths the language has quotes of are offered, and switching
+	// to a language without the current length goes back to all.
+	for _, length := range m.quotes.Lengths(s.Quote.Language) {
+		commands = append(commands, m.setting("Quote length", length.String(), s.Quote.Length == length.String(), func() {
+			s.Quote.Length = length.String()
+		}))
+	}
+	for _, language := range m.quotes.Languages() {
+		commands = append(commands, m.setting("Quote language", language, s.Quote.Language == language, func() {
+			s.Quote.Language = language
+			length, _ := quote.ParseLength(s.Quote.Length)
+			if !slices.Contains(m.quotes.Lengths(language), length) {
+				s.Quote.Length = quote.LengthAll.String()
+			}
+		}))
+	}
+	commands = append(commands,
+		m.setting("Quote pool", "all", !s.Quote.FavoritesOnly, func() { s.Quote.FavoritesOnly = false }),
+		m.setting("Quote pool", "favorites", s.Quote.FavoritesOnly, func() { s.Quote.FavoritesOnly = true }),
+	)
+	for _, words := range []int{10, 25, 50, 100} {
+		commands = append(commands, m.setting("Words", strconv.Itoa(words), s.Words == words, func() {
+			s.Words = words
+		}))
+	}
+	for _, difficulty := range []string{"normal", "expert", "master"} {
+		commands = append(commands, m.setting("Difficulty", difficulty, s.Difficulty == difficulty, func() {
+			s.Difficulty = difficulty
+		}))
+	}
+	for _, wpm := range []float64{0, 30, 60, 90} {
+		commands = append(commands, m.setting("Minimum WPM", minimumTitle(wpm, ""), s.Minimum.WPM == wpm, func() {
+			s.Minimum.WPM = wpm
+		}))
+	}
+	for _, accuracy := range []float64{0, 90, 95, 98} {
+		commands = append(commands, m.setting("Minimum accuracy", minimumTitle(accuracy, "%"), s.Minimum.Accuracy == accuracy, func() {
+			s.Minimum.Accuracy = accuracy
+		}))
+	}
+	for _, burst := range []float64{0, 40, 70, 100} {
+		commands = append(commands, m.setting("Minimum burst", minimumTitle(burst, ""), s.Minimum.Burst == burst, func() {
+			s.Minimum.Burst = burst
+		}))
+	}
+	enabled, _ := funbox.Parse(s.Funbox)
+	for _, f := range funbox.Builtin() {
+		active := slices.Contains(enabled.Names(), f.Name)
+		commands = append(commands, m.setting("Funbox", f.Name, active, func() {
+			s.Funbox = toggleFunbox(enabled.Names(), f.Name, !active)
+		}))
+	}
+	for _, layout := range []string{"box", "tape"} {
+		commands = append(commands, m.setting("Layout", layout, s.Layout == layout, func() {
+			s.Layout = layout
+		}))
+	}
+	for _, scroll := range []string{"letter", "word"} {
+		commands = append(commands, m.setting("Tape scroll", scroll, s.Tape.Scroll == scroll, func() {
+			s.Tape.Scroll = scroll
+		}))
+	}
+	for _, lines := range []int{1, 2, 3, 5, 0} {
+		title := strconv.Itoa(lines)
+		if lines == 0 {
+			title = "all"
+		}
+		commands = append(commands, m.setting("Visible lines", title, s.VisibleLines == lines, func() {
+			s.VisibleLines = lines
+		}))
+	}
+	for _, style := range []string{"block", "line", "underline", "outline", "off"} {
+		commands = append(commands, m.setting("Caret", style, s.Caret.Style == style, func() {
+			s.Caret.Style = style
+		}))
+	}
+	commands = append(commands,
+		m.setting("Caret blink", "on", s.Caret.Blink, func() { s.Caret.Blink = true }),
+		m.setting("Caret blink", "off", !s.Caret.Blink, func() { s.Caret.Blink = false }),
+	)
+	for _, pace := range []string{"off", "wpm", "pb", "average"} {
+		commands = append(commands, m.setting("Pace caret", pace, s.Caret.Pace == pace, func() {
+			s.Caret.Pace = pace
+		}))
+	}
+	commands = append(commands,
+		m.setting("Freedom mode", "on", s.Input.Freedom, func() { s.Input.Freedom = true }),
+		m.setting("Freedom mode", "off", !s.Input.Freedom, func() { s.Input.Freedom = false }),
+	)
+	for _, stop := range []string{"off", "letter", "word"} {
+		commands = append(commands, m.setting("Stop on error", stop, s.Input.StopOnError == stop, func() {
+			s.Input.StopOnError = stop
+		}))
+	}
+	commands = append(commands,
+		m.setting("Blind mode", "on", s.Input.Blind, func() { s.Input.Blind = true }),
+		m.setting("Blind mode", "off", !s.Input.Blind, func() { s.Input.Blind = false }),
+	)
+	commands = append(commands,
+		m.setting("Lenient matching", "on", s.Input.Lenient, func() { s.Input.Lenient = true }),
+		m.setting("Lenient matching", "off", !s.Input.Lenient, func() { s.Input.Lenient = false }),
+		m.setting("Ignore case", "on", s.Input.IgnoreCase, func() { s.Input.IgnoreCase = true }),
+		m.setting("Ignore case", "off", !s.Input.IgnoreCase, func() { s.Input.IgnoreCase = false }),
+	)
+	for _, paste := range []string{"reject", "record"} {
+		commands = append(commands, m.setting("Paste", paste, s.Input.Paste == paste, func() {
+			s.Input.Paste = paste
+		}))
+	}
+	commands = append(commands,
+		m.setting("Pause on focus loss", "on", s.Pause.OnBlur, func() { s.Pause.OnBlur = true }),
+		m.setting("Pause on focus loss", "off", !s.Pause.OnBlur, func() { s.Pause.OnBlur = false }),
+		m.setting("Paused results in PBs", "counted", !s.Pause.ExcludePaused, func() { s.Pause.ExcludePaused = false }),
+		m.setting("Paused results in PBs", "excluded", s.Pause.ExcludePaused, func() { s.Pause.ExcludePaused = true }),
+	)
+	for _, seconds := range []int{0, 5, 10, 30} {
+		title := fmt.Sprintf("after %ds", seconds)
+		if seconds == 0 {
+			title = "never"
+		}
+		commands = append(commands, m.setting("Confirm restart", title, s.QuickRestart.ConfirmAfter == seconds, func() {
+			s.QuickRestart.ConfirmAfter = seconds
+		}))
+	}
+
+	for _, color := range []string{"auto", "truecolor", "256", "16", "none"} {
+		commands = append(commands, m.displaySetting("Color", color, s.Color == color, func() {
+			s.Color = color
+		}))
+	}
+	for _, mode := range []string{"none", "red-green", "tritanopia"} {
+		commands = append(commands, m.displaySetting("Colorblind", mode, s.Accessibility.Colorblind == mode, func() {
+			s.Accessibility.Colorblind = mode
+		}))
+	}
+	commands = append(commands,
+		m.displaySetting("High contrast", "on", s.Accessibility.HighContrast, func() { s.Accessibility.HighContrast = true }),
+		m.displaySetting("High contrast", "off", !s.Accessibility.HighContrast, func() { s.Accessibility.HighContrast = false }),
+	)
+	for _, marking := range []string{"color", "underline", "glyph"} {
+		commands = append(commands, m.displaySetting("Error marking", marking, s.Accessibility.ErrorMarking == marking, func() {
+			s.Accessibility.ErrorMarking = marking
+		}))
+	}
+	for _, position := range []string{"off", "above", "below"} {
+		commands = append(commands, m.displaySetting("Expected char", position, s.Accessibility.ExpectedChar == position, func() {
+			s.Accessibility.ExpectedChar = position
+		}))
+	}
+
+	return commands
+}
+
+// toggleFunbox turns name on or off in names, keeping menu order.
+func toggleFunbox(names []string, name string, on bool) []string {
+	var toggled []string
+	for _, f := range funbox.Builtin() {
+		if f.Name == name && on || f.Name != name && slices.Contains(names, f.Name) {
+			toggled = append(toggled, f.Name)
+		}
+	}
+	return toggled
+}
+
+func minimumTitle(value float64, unit string) string {
+	if value == 0 {
+		return "off"
+	}
+	return fmt.Sprintf("%.0f%s", value, unit)
+}
+
+// setting builds a palette entry that changes a typing setting, applies it
+// straight away and saves the config.
+func (m *Model) setting(group, title string, active bool, change func()) palette.Command {
+	return palette.Command{
+		Group:  group,
+		Title:  title,
+		Active: active,
+		Run: func() tea.Cmd {
+			change()
+			return m.settingsChanged(m.applySettings())
+		},
+	}
+}
+
+// displaySetting is setting for the color and accessibility options, which
+// rebuild the shared styles rather than the typing model.
+func (m *Model) displaySetting(group, title string, active bool, change func()) palette.Command {
+	return palette.Command{
+		Group:  group,
+		Title:  title,
+		Active: active,
+		Run: func() tea.Cmd {
+			change()
+			return m.settingsChanged(m.applyDisplaySettings())
+		},
+	}
+}
+
+func (m *Model) applyDisplaySettings() error {
+	colorMode, colorErr := shared.DetectColorMode(m.settings.Color)
+	shared.SetColorMode(colorMode, shared.HasDarkBackground())
+
+	accessibility, accessibilityErr := shared.AccessibilityFromConfig(m.settings.Accessibility)
+	shared.SetAccessibility(accessibility)
+
+	return errors.Join(colorErr, accessibilityErr)
+}
+
+func (m *Model) settingsChanged(err error) tea.Cmd {
+	if err != nil {
+		return tea.Batch(saveSettings(m.settings), m.reloadGenerator(), func() tea.Msg {
+			return shared.ErrMsg{Err: err}
+		})
+	}
+	return tea.Batch(saveSettings(m.settings), m.reloadGenerator())
+}
+
+// copyResult puts a one-line summary of the last result on the clipboard
+// with OSC 52, which also works over SSH in terminals that allow it. The
+// sequence goes out with the next frames rather than straight to the
+// terminal, so it cannot land in the middle of one.
+func (m *Model) copyResult() tea.Cmd {
+	result := m.lastResult
+	summary := fmt.Sprintf("aiotype: %.1f wpm, %.1f%% accuracy, %d/%d words, %.1fs",
+		result.WPM, result.Accuracy, result.CorrectWords, result.TotalWords, result.TestDuration.Seconds())
+	m.resultsModel.SetStatus("Result copied to clipboard")
+
+	var sequence strings.Builder
+	termenv.NewOutput(&sequence).Copy(summary)
+	m.clipboard = sequence.String()
+	return tea.Tick(clipboardHold, func(time.Time) tea.Msg {
+		return clipboardSentMsg{sequence: sequence.String()}
+	})
+}
+
+// clipboardHold is how long a copy stays in the view, several frames of
+// the renderer, so at least one of them reaches the terminal.
+const clipboardHold = 200 * time.Millisecond
+
+// clipboardSentMsg takes a copy back out of the view once it has been
+// drawn.
+type clipboardSentMsg struct {
+	sequence string
+}
@@ -0,0 +1,47 @@
+package failed
+
+import (
+	"aiotype/internal"
+	"aiotype/internal/ui/keymap"
+	"github.com/charmbracelet/bubbletea"
+)
+
+// Model shows a test that ended early under expert or master difficulty.
+// It shares its keys with the results screen.
+type Model struct {
+	keys   keymap.ResultsKeyMap
+	result *internal.TestResult
+	// failures and runs count the failed and all tests at the result's
+	// difficulty, from the history.
+	failures     int
+	runs         int
+	windowWidth  int
+	windowHeight int
+}
+
+func NewModel(keys keymap.ResultsKeyMap) *Model {
+	return &Model{keys: keys}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.windowWidth = msg.Width
+		m.windowHeight = msg.Height
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m *Model) SetResult(result *internal.TestResult) {
+	m.result = result
+}
+
+func (m *Model) SetFailures(failures, runs int) {
+	m.failures = failures
+	m.runs = runs
+}
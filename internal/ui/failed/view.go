@@ -0,0 +1,50 @@
+package failed
+
+import (
+	"fmt"
+
+	"aiotype/internal/ui/shared"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func (m *Model) View() string {
+	if m.result == nil {
+		return ""
+	}
+
+	styles := shared.Styles()
+	title := styles.ResultTitle.Render("✗ Test Failed")
+	reason := styles.ErrorText.Render(m.result.Failed)
+
+	stats := []string{
+		fmt.Sprintf("%s %s", styles.StatLabel.Render("Difficulty:"), styles.StatValue.Render(m.result.Difficulty.String())),
+		fmt.Sprintf("%s %s", styles.StatLabel.Render("Failed on:"), styles.ErrorText.Render(m.result.FailedWord)),
+		fmt.Sprintf("%s %s", styles.StatLabel.Render("Words:"), styles.StatValue.Render(fmt.Sprintf("%d/%d", m.result.CorrectWords, m.result.TotalWords))),
+		fmt.Sprintf("%s %s", styles.StatLabel.Render("WPM so far:"), styles.StatValue.Render(fmt.Sprintf("%.1f", m.result.WPM))),
+		fmt.Sprintf("%s %s", styles.StatLabel.Render("Time:"), styles.StatValue.Render(fmt.Sprintf("%.1fs", m.result.TestDuration.Seconds()))),
+	}
+	if m.runs > 0 {
+		stats = append(stats, fmt.Sprintf("%s %s", styles.StatLabel.Render("Failed runs:"), styles.StatValue.Render(fmt.Sprintf("%d of %d", m.failures, m.runs))))
+	}
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Center,
+		title,
+		reason,
+		"",
+		lipgloss.JoinVertical(lipgloss.Left, stats...),
+		"",
+		styles.SubText.Render("kept apart from your results"),
+		shared.HelpLine(m.keys),
+	)
+
+	container := styles.ResultsContainer.Width(50).Render(content)
+
+	return lipgloss.Place(
+		m.windowWidth,
+		m.windowHeight,
+		lipgloss.Center,
+		lipgloss.Center,
+		container,
+	)
+}
@@ -0,0 +1,75 @@
+package funboxes
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbletea"
+
+	"aiotype/internal/funbox"
+	"aiotype/internal/ui/keymap"
+)
+
+type Model struct {
+	keys         keymap.FunboxKeyMap
+	funboxes     []funbox.Funbox
+	enabled      map[string]bool
+	cursor       int
+	windowWidth  int
+	windowHeight int
+}
+
+func NewModel(keys keymap.FunboxKeyMap) *Model {
+	return &Model{
+		keys:     keys,
+		funboxes: funbox.Builtin(),
+		enabled:  map[string]bool{},
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.windowWidth = msg.Width
+		m.windowHeight = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			m.move(-1)
+		case key.Matches(msg, m.keys.Down):
+			m.move(1)
+		case key.Matches(msg, m.keys.Toggle):
+			name := m.funboxes[m.cursor].Name
+			m.enabled[name] = !m.enabled[name]
+		}
+	}
+	return m, nil
+}
+
+// Open starts from the funboxes in names, which is what the config holds.
+func (m *Model) Open(names []string) {
+	set, _ := funbox.Parse(names)
+	m.enabled = map[string]bool{}
+	for _, name := range set.Names() {
+		m.enabled[name] = true
+	}
+}
+
+// Selected lists the enabled funboxes in menu order.
+func (m *Model) Selected() []string {
+	var names []string
+	for _, f := range m.funboxes {
+		if m.enabled[f.Name] {
+			names = append(names, f.Name)
+		}
+	}
+	return names
+}
+
+func (m *Model) move(delta int) {
+	m.cursor = (m.cursor + delta + len(m.funboxes)) % len(m.funboxes)
+}
@@ -0,0 +1,47 @@
+package funboxes
+
+import (
+	"strings"
+
+	"aiotype/internal/ui/shared"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const listWidth = 56
+
+func (m *Model) View() string {
+	styles := shared.Styles()
+	title := styles.Title.Render("Funbox")
+
+	lines := make([]string, 0, len(m.funboxes))
+	for i, f := range m.funboxes {
+		box := "[ ] "
+		if m.enabled[f.Name] {
+			box = "[x] "
+		}
+		name := box + f.Name
+		if i == m.cursor {
+			name = styles.Selected.Render("> " + name)
+		} else {
+			name = "  " + name
+		}
+		lines = append(lines, name+" "+styles.SubText.Render("· "+f.Description))
+	}
+	list := lipgloss.NewStyle().Width(listWidth).Render(strings.Join(lines, "\n"))
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Center,
+		title,
+		list,
+		"",
+		shared.HelpLine(m.keys),
+	)
+
+	return lipgloss.Place(
+		m.windowWidth,
+		m.windowHeight,
+		lipgloss.Center,
+		lipgloss.Center,
+		content,
+	)
+}
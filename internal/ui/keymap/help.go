@@ -0,0 +1,97 @@
+package keymap
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+)
+
+func (k GlobalKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Palette, k.ForceQuit}
+}
+
+func (k GlobalKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+type withGlobal struct {
+	screen help.KeyMap
+	global GlobalKeyMap
+}
+
+// WithGlobal adds the bindings that work on every screen as an extra help
+// column, for the full help overlay.
+func WithGlobal(screen help.KeyMap, global GlobalKeyMap) help.KeyMap {
+	return withGlobal{screen: screen, global: global}
+}
+
+func (k withGlobal) ShortHelp() []key.Binding {
+	return k.screen.ShortHelp()
+}
+
+func (k withGlobal) FullHelp() [][]key.Binding {
+	return append(k.screen.FullHelp(), k.global.ShortHelp())
+}
+
+func (k MenuKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Start, k.Lessons, k.Themes, k.Funbox, k.Quotes, k.Help, k.Quit}
+}
+
+func (k MenuKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Start, k.Lessons, k.Themes, k.Funbox, k.Quotes}, {k.Help, k.Quit}}
+}
+
+func (k TypingKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Restart, k.Repeat, k.Pause, k.Menu, k.Help}
+}
+
+func (k TypingKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Backspace, k.DeleteWord, k.Pause, k.Restart, k.Repeat}, {k.Menu, k.Confirm, k.Help}}
+}
+
+func (k ResultsKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Restart, k.Repeat, k.Menu, k.Quit}
+}
+
+func (k ResultsKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Restart, k.Repeat}, {k.Favorite, k.Rate}, {k.Menu, k.Help, k.Quit}}
+}
+
+func (k ThemesKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Apply, k.Cancel}
+}
+
+func (k ThemesKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Up, k.Down, k.Top, k.Bottom}, {k.Apply, k.Cancel, k.Help}}
+}
+
+func (k FunboxKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Toggle, k.Done}
+}
+
+func (k FunboxKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Up, k.Down}, {k.Toggle, k.Done, k.Help}}
+}
+
+func (k QuotesKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Start, k.Favorite, k.Close}
+}
+
+func (k QuotesKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Up, k.Down}, {k.Start, k.Favorite, k.Close, k.Help}}
+}
+
+func (k LessonMapKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Start, k.Back}
+}
+
+func (k LessonMapKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Up, k.Down}, {k.Start, k.Back, k.Help}}
+}
+
+func (k PaletteKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Run, k.Close}
+}
+
+func (k PaletteKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Up, k.Down}, {k.Run, k.Close}}
+}
@@ -0,0 +1,315 @@
+package keymap
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+var (
+	ErrUnknownScreen = errors.New("unknown keymap screen")
+	ErrUnknownAction = errors.New("unknown keymap action")
+)
+
+type GlobalKeyMap struct {
+	ForceQuit key.Binding
+	Palette   key.Binding
+}
+
+type MenuKeyMap struct {
+	Start   key.Binding
+	Themes  key.Binding
+	Funbox  key.Binding
+	Quotes  key.Binding
+	Lessons key.Binding
+	Help    key.Binding
+	Quit    key.Binding
+}
+
+type TypingKeyMap struct {
+	Backspace  key.Binding
+	DeleteWord key.Binding
+	Pause      key.Binding
+	Restart    key.Binding
+	Repeat     key.Binding
+	Menu       key.Binding
+	Confirm    key.Binding
+	// Help cannot be "?" here, since that is a character you may need
+	// to type.
+	Help key.Binding
+}
+
+type ResultsKeyMap struct {
+	Restart  key.Binding
+	Repeat   key.Binding
+	Menu     key.Binding
+	Favorite key.Binding
+	Rate     key.Binding
+	Help     key.Binding
+	Quit     key.Binding
+}
+
+type ThemesKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Top    key.Binding
+	Bottom key.Binding
+	Apply  key.Binding
+	Cancel key.Binding
+	Help   key.Binding
+}
+
+type FunboxKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Toggle key.Binding
+	Done   key.Binding
+	Help   key.Binding
+}
+
+type QuotesKeyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	Start    key.Binding
+	Favorite key.Binding
+	Close    key.Binding
+	Help     key.Binding
+}
+
+type LessonMapKeyMap struct {
+	Up    key.Binding
+	Down  key.Binding
+	Start key.Binding
+	Back  key.Binding
+	Help  key.Binding
+}
+
+type PaletteKeyMap struct {
+	Up    key.Binding
+	Down  key.Binding
+	Run   key.Binding
+	Close key.Binding
+}
+
+type KeyMap struct {
+	Global    GlobalKeyMap
+	Menu      MenuKeyMap
+	Typing    TypingKeyMap
+	Results   ResultsKeyMap
+	Themes    ThemesKeyMap
+	Funbox    FunboxKeyMap
+	Quotes    QuotesKeyMap
+	LessonMap LessonMapKeyMap
+	Palette   PaletteKeyMap
+}
+
+func Default() KeyMap {
+	return KeyMap{
+		Global: GlobalKeyMap{
+			ForceQuit: key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit")),
+			Palette:   key.NewBinding(key.WithKeys("ctrl+p"), key.WithHelp("ctrl+p", "command palette")),
+		},
+		Menu: MenuKeyMap{
+			Start:   key.NewBinding(key.WithKeys("enter", " "), key.WithHelp("enter/space", "start typing")),
+			Themes:  key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "themes")),
+			Funbox:  key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "funbox")),
+			Quotes:  key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "quotes")),
+			Lessons: key.NewBinding(key.WithKeys("l"), key.WithHelp("l", "lessons")),
+			Help:    key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+			Quit:    key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+		},
+		Typing: TypingKeyMap{
+			Backspace: key.NewBinding(key.WithKeys("backspace"), key.WithHelp("backspace", "delete char")),
+			// Most terminals send ctrl+backspace as ctrl+h, but some send it
+			// for plain backspace too, so it is left for users to add under
+			// [keys.typing].
+			DeleteWord: key.NewBinding(key.WithKeys("ctrl+w", "alt+backspace"), key.WithHelp("ctrl+w", "delete word")),
+			Pause:      key.NewBinding(key.WithKeys("f2"), key.WithHelp("f2", "pause")),
+			Restart:    key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "restart")),
+			Repeat:     key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "repeat")),
+			Menu:       key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "menu")),
+			Confirm:    key.NewBinding(key.WithKeys("enter", "y"), key.WithHelp("enter/y", "confirm")),
+			Help:       key.NewBinding(key.WithKeys("f1"), key.WithHelp("f1", "help")),
+		},
+		Results: ResultsKeyMap{
+			Restart: key.NewBinding(key.WithKeys("enter", " ", "r", "tab"), key.WithHelp("enter/tab", "restart")),
+			Repeat:  key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "repeat")),
+			Menu:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "menu")),
+			// Favorite and Rate only apply to quotes.
+			Favorite: key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "favorite quote")),
+			Rate:     key.NewBinding(key.WithKeys("1", "2", "3", "4", "5"), key.WithHelp("1-5", "rate quote")),
+			Help:     key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+			Quit:     key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+		},
+		Themes: ThemesKeyMap{
+			Up:     key.NewBinding(key.WithKeys("up", "k", "shift+tab"), key.WithHelp("↑/k", "previous")),
+			Down:   key.NewBinding(key.WithKeys("down", "j", "tab"), key.WithHelp("↓/j", "next")),
+			Top:    key.NewBinding(key.WithKeys("home", "g"), key.WithHelp("g", "first")),
+			Bottom: key.NewBinding(key.WithKeys("end", "G"), key.WithHelp("G", "last")),
+			Apply:  key.NewBinding(key.WithKeys("enter", " "), key.WithHelp("enter", "apply")),
+			Cancel: key.NewBinding(key.WithKeys("esc", "q"), key.WithHelp("esc", "cancel")),
+			Help:   key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+		},
+		Funbox: FunboxKeyMap{
+			Up:     key.NewBinding(key.WithKeys("up", "k", "shift+tab"), key.WithHelp("↑/k", "previous")),
+			Down:   key.NewBinding(key.WithKeys("down", "j", "tab"), key.WithHelp("↓/j", "next")),
+			Toggle: key.NewBinding(key.WithKeys(" ", "enter"), key.WithHelp("space", "toggle")),
+			Done:   key.NewBinding(key.WithKeys("esc", "q"), key.WithHelp("esc", "done")),
+			Help:   key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+		},
+		Quotes: QuotesKeyMap{
+			Up:       key.NewBinding(key.WithKeys("up", "ctrl+k"), key.WithHelp("↑", "previous")),
+			Down:     key.NewBinding(key.WithKeys("down", "ctrl+j"), key.WithHelp("↓", "next")),
+			Start:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "type quote")),
+			Favorite: key.NewBinding(key.WithKeys("ctrl+f"), key.WithHelp("ctrl+f", "favorite")),
+			Close:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "close")),
+			// The search box takes every printable key, so help is on f1
+			// as it is while typing.
+			Help: key.NewBinding(key.WithKeys("f1"), key.WithHelp("f1", "help")),
+		},
+		LessonMap: LessonMapKeyMap{
+			Up:    key.NewBinding(key.WithKeys("up", "k", "shift+tab"), key.WithHelp("↑/k", "previous")),
+			Down:  key.NewBinding(key.WithKeys("down", "j", "tab"), key.WithHelp("↓/j", "next")),
+			Start: key.NewBinding(key.WithKeys("enter", " "), key.WithHelp("enter", "start lesson")),
+			Back:  key.NewBinding(key.WithKeys("esc", "q"), key.WithHelp("esc", "back")),
+			Help:  key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+		},
+		Palette: PaletteKeyMap{
+			Up:    key.NewBinding(key.WithKeys("up", "ctrl+k"), key.WithHelp("↑", "previous")),
+			Down:  key.NewBinding(key.WithKeys("down", "ctrl+j"), key.WithHelp("↓", "next")),
+			Run:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "run")),
+			Close: key.NewBinding(key.WithKeys("esc", "ctrl+p"), key.WithHelp("esc", "close")),
+		},
+	}
+}
+
+// Apply rebinds actions from the config file, where overrides maps a
+// screen name to action names and their new keys, e.g. menu.quit = ["ctrl+q"].
+// An empty key list unbinds the action.
+func (k *KeyMap) Apply(overrides map[string]map[string][]string) error {
+	var errs []error
+	for _, screen := range sortedKeys(overrides) {
+		bindings, ok := k.screens()[screen]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: %q", ErrUnknownScreen, screen))
+			continue
+		}
+		for _, action := range sortedKeys(overrides[screen]) {
+			binding, ok := bindings[action]
+			if !ok {
+				errs = append(errs, fmt.Errorf("%w: %s.%s", ErrUnknownAction, screen, action))
+				continue
+			}
+			rebind(binding, overrides[screen][action])
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (k *KeyMap) screens() map[string]map[string]*key.Binding {
+	return map[string]map[string]*key.Binding{
+		"global": {
+			"force_quit": &k.Global.ForceQuit,
+			"palette":    &k.Global.Palette,
+		},
+		"menu": {
+			"start":   &k.Menu.Start,
+			"themes":  &k.Menu.Themes,
+			"funbox":  &k.Menu.Funbox,
+			"quotes":  &k.Menu.Quotes,
+			"lessons": &k.Menu.Lessons,
+			"help":    &k.Menu.Help,
+			"quit":    &k.Menu.Quit,
+		},
+		"typing": {
+			"backspace":   &k.Typing.Backspace,
+			"delete_word": &k.Typing.DeleteWord,
+			"pause":       &k.Typing.Pause,
+			"restart":     &k.Typing.Restart,
+			"repeat":      &k.Typing.Repeat,
+			"menu":        &k.Typing.Menu,
+			"confirm":     &k.Typing.Confirm,
+			"help":        &k.Typing.Help,
+		},
+		"results": {
+			"restart":  &k.Results.Restart,
+			"repeat":   &k.Results.Repeat,
+			"menu":     &k.Results.Menu,
+			"favorite": &k.Results.Favorite,
+			"rate":     &k.Results.Rate,
+			"help":     &k.Results.Help,
+			"quit":     &k.Results.Quit,
+		},
+		"themes": {
+			"up":     &k.Themes.Up,
+			"down":   &k.Themes.Down,
+			"top":    &k.Themes.Top,
+			"bottom": &k.Themes.Bottom,
+			"apply":  &k.Themes.Apply,
+			"cancel": &k.Themes.Cancel,
+			"help":   &k.Themes.Help,
+		},
+		"funbox": {
+			"up":     &k.Funbox.Up,
+			"down":   &k.Funbox.Down,
+			"toggle": &k.Funbox.Toggle,
+			"done":   &k.Funbox.Done,
+			"help":   &k.Funbox.Help,
+		},
+		"quotes": {
+			"up":       &k.Quotes.Up,
+			"down":     &k.Quotes.Down,
+			"start":    &k.Quotes.Start,
+			"favorite": &k.Quotes.Favorite,
+			"close":    &k.Quotes.Close,
+			"help":     &k.Quotes.Help,
+		},
+		"lessons": {
+			"up":    &k.LessonMap.Up,
+			"down":  &k.LessonMap.Down,
+			"start": &k.LessonMap.Start,
+			"back":  &k.LessonMap.Back,
+			"help":  &k.LessonMap.Help,
+		},
+		"palette": {
+			"up":    &k.Palette.Up,
+			"down":  &k.Palette.Down,
+			"run":   &k.Palette.Run,
+			"close": &k.Palette.Close,
+		},
+	}
+}
+
+func rebind(binding *key.Binding, keys []string) {
+	if len(keys) == 0 {
+		binding.SetEnabled(false)
+		return
+	}
+	binding.SetKeys(keys...)
+	binding.SetHelp(Describe(*binding), binding.Help().Desc)
+	binding.SetEnabled(true)
+}
+
+// Describe renders a binding's keys for help text, e.g. "enter/space".
+func Describe(binding key.Binding) string {
+	keys := make([]string, 0, len(binding.Keys()))
+	for _, k := range binding.Keys() {
+		if k == " " {
+			k = "space"
+		}
+		keys = append(keys, k)
+	}
+	return strings.Join(keys, "/")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,85 @@
+package lessonmap
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbletea"
+
+	"aiotype/internal/curriculum"
+	"aiotype/internal/ui/keymap"
+)
+
+type Model struct {
+	keys         keymap.LessonMapKeyMap
+	course       *curriculum.Curriculum
+	progress     *curriculum.Progress
+	cursor       int
+	status       string
+	windowWidth  int
+	windowHeight int
+}
+
+func NewModel(keys keymap.LessonMapKeyMap, course *curriculum.Curriculum) *Model {
+	return &Model{
+		keys:   keys,
+		course: course,
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.windowWidth = msg.Width
+		m.windowHeight = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			m.move(-1)
+		case key.Matches(msg, m.keys.Down):
+			m.move(1)
+		}
+	}
+	return m, nil
+}
+
+// Open shows the course against progress, with the cursor on the first
+// open lesson not yet passed.
+func (m *Model) Open(progress *curriculum.Progress) {
+	m.progress = progress
+	m.status = ""
+	m.cursor = 0
+	for i, lesson := range m.course.Lessons() {
+		if m.course.Unlocked(progress, lesson.ID) && !progress.Passed(lesson.ID) {
+			m.cursor = i
+			break
+		}
+	}
+}
+
+// Selected is the lesson under the cursor, if it is open.
+func (m *Model) Selected() (curriculum.Lesson, bool) {
+	lessons := m.course.Lessons()
+	if len(lessons) == 0 {
+		return curriculum.Lesson{}, false
+	}
+	lesson := lessons[m.cursor]
+	return lesson, m.course.Unlocked(m.progress, lesson.ID)
+}
+
+func (m *Model) SetStatus(status string) {
+	m.status = status
+}
+
+func (m *Model) move(delta int) {
+	lessons := len(m.course.Lessons())
+	if lessons == 0 {
+		return
+	}
+	m.cursor = (m.cursor + delta + lessons) % lessons
+	m.status = ""
+}
@@ -0,0 +1,90 @@
+package lessonmap
+
+import (
+	"fmt"
+	"strings"
+
+	"aiotype/internal/ui/shared"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	listWidth   = 60
+	detailWidth = 72
+	titleWidth  = 14
+)
+
+func (m *Model) View() string {
+	styles := shared.Styles()
+	title := styles.Title.Render("Lessons")
+
+	lessons := m.course.Lessons()
+	lines := make([]string, 0, len(lessons))
+	for i, lesson := range lessons {
+		mark, detail := "·", "locked"
+		attempt := m.progress.Lessons[lesson.ID]
+		switch {
+		case attempt.Passed:
+			mark = "✓"
+			detail = fmt.Sprintf("best %.0f wpm • %.0f%%", attempt.BestWPM, attempt.BestAccuracy)
+		case m.course.Unlocked(m.progress, lesson.ID):
+			mark, detail = "▸", "open"
+			if attempt.Tries > 0 {
+				detail = fmt.Sprintf("best %.0f wpm • %.0f%%", attempt.BestWPM, attempt.BestAccuracy)
+			}
+		}
+
+		name := fmt.Sprintf("%s %-*s", mark, titleWidth, lesson.Title)
+		if i == m.cursor {
+			name = styles.Selected.Render("> " + name)
+		} else {
+			name = "  " + name
+		}
+		lines = append(lines, name+" "+styles.SubText.Render(detail))
+	}
+	list := lipgloss.NewStyle().Width(listWidth).Render(strings.Join(lines, "\n"))
+
+	content := []string{title, list, ""}
+	if len(lessons) > 0 {
+		content = append(content, m.renderDetail())
+	}
+	if m.status != "" {
+		content = append(content, "", styles.ErrorText.Render(m.status))
+	}
+	content = append(content, "", shared.HelpLine(m.keys))
+
+	return lipgloss.Place(
+		m.windowWidth,
+		m.windowHeight,
+		lipgloss.Center,
+		lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Center, content...),
+	)
+}
+
+// renderDetail describes the lesson under the cursor: what it practices,
+// what passes it and what it unlocks.
+func (m *Model) renderDetail() string {
+	styles := shared.Styles()
+	lesson := m.course.Lessons()[m.cursor]
+
+	keys := lesson.Focus
+	if keys == "" {
+		keys = lesson.Keys
+	}
+	criteria := fmt.Sprintf("keys %s • pass at %.0f wpm and %.0f%%", keys, lesson.PassWPM, lesson.PassAccuracy)
+	if len(lesson.Unlocks) > 0 {
+		var titles []string
+		for _, id := range lesson.Unlocks {
+			if unlocked, err := m.course.Find(id); err == nil {
+				titles = append(titles, unlocked.Title)
+			}
+		}
+		criteria += " • unlocks " + strings.Join(titles, ", ")
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Center,
+		lipgloss.NewStyle().Width(detailWidth).Align(lipgloss.Center).Render(lesson.Description),
+		styles.SubText.Width(detailWidth).Align(lipgloss.Center).Render(criteria),
+	)
+}
@@ -2,15 +2,19 @@ package menu
 
 import (
 	"github.com/charmbracelet/bubbletea"
+
+	"aiotype/internal/ui/keymap"
 )
 
 type Model struct {
+	keys         keymap.MenuKeyMap
 	windowWidth  int
 	windowHeight int
+	status       string
 }
 
-func NewModel() *Model {
-	return &Model{}
+func NewModel(keys keymap.MenuKeyMap) *Model {
+	return &Model{keys: keys}
 }
 
 func (m *Model) Init() tea.Cmd {
@@ -26,3 +30,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 	return m, nil
 }
+
+func (m *Model) SetStatus(status string) {
+	m.status = status
+}
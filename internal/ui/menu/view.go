@@ -6,12 +6,9 @@ import (
 )
 
 func (m *Model) View() string {
-	title := shared.TitleStyle.Render("aiotype")
-	subtitle := shared.SubtitleStyle.Render("A Typoing game inspired by monkeytype")
-	instructions := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#646669")).
-		Align(lipgloss.Center).
-		Render("Press ENTER or SPACE to start typing • Q to quit")
+	title := shared.Styles().Title.Render("aiotype")
+	subtitle := shared.Styles().Subtitle.Render("A Typoing game inspired by monkeytype")
+	instructions := shared.HelpLine(m.keys)
 
 	content := lipgloss.JoinVertical(
 		lipgloss.Center,
@@ -20,6 +17,15 @@ func (m *Model) View() string {
 		instructions,
 	)
 
+	if m.status != "" {
+		content = lipgloss.JoinVertical(
+			lipgloss.Center,
+			content,
+			"",
+			shared.Styles().ErrorText.Render(m.status),
+		)
+	}
+
 	return lipgloss.Place(
 		m.windowWidth,
 		m.windowHeight,
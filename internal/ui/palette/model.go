@@ -0,0 +1,136 @@
+package palette
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/sahilm/fuzzy"
+
+	"aiotype/internal/ui/keymap"
+)
+
+type Command struct {
+	Group string
+	Title string
+	// Active marks the current value of a setting.
+	Active bool
+	Run    func() tea.Cmd
+}
+
+func (c Command) label() string {
+	if c.Group == "" {
+		return c.Title
+	}
+	return c.Group + ": " + c.Title
+}
+
+type match struct {
+	command Command
+	indexes []int
+}
+
+type Model struct {
+	keys         keymap.PaletteKeyMap
+	input        textinput.Model
+	commands     []Command
+	matches      []match
+	cursor       int
+	windowWidth  int
+	windowHeight int
+}
+
+func NewModel(keys keymap.PaletteKeyMap) *Model {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "type a command, setting or theme"
+	input.CharLimit = 64
+	input.Width = paletteWidth - 4
+
+	return &Model{
+		keys:  keys,
+		input: input,
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.windowWidth = msg.Width
+		m.windowHeight = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Down):
+			if m.cursor < len(m.matches)-1 {
+				m.cursor++
+			}
+			return m, nil
+		}
+	}
+
+	previous := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != previous {
+		m.filter()
+	}
+	return m, cmd
+}
+
+// Open resets the query and shows commands, which the caller rebuilds on
+// every open so settings show their current values.
+func (m *Model) Open(commands []Command) tea.Cmd {
+	m.commands = commands
+	m.input.SetValue("")
+	m.filter()
+	return m.input.Focus()
+}
+
+func (m *Model) Close() {
+	m.input.Blur()
+}
+
+// Selected returns the highlighted command, if any command matches.
+func (m *Model) Selected() (Command, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.matches) {
+		return Command{}, false
+	}
+	return m.matches[m.cursor].command, true
+}
+
+func (m *Model) filter() {
+	m.cursor = 0
+	query := m.input.Value()
+
+	if query == "" {
+		m.matches = make([]match, len(m.commands))
+		for i, c := range m.commands {
+			m.matches[i] = match{command: c}
+		}
+		return
+	}
+
+	labels := make([]string, len(m.commands))
+	for i, c := range m.commands {
+		labels[i] = c.label()
+	}
+
+	results := fuzzy.Find(query, labels)
+	m.matches = make([]match, len(results))
+	for i, result := range results {
+		m.matches[i] = match{
+			command: m.commands[result.Index],
+			indexes: result.MatchedIndexes,
+		}
+	}
+}
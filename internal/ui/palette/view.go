@@ -0,0 +1,81 @@
+package palette
+
+import (
+	"strings"
+
+	"aiotype/internal/ui/shared"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	visibleCommands = 10
+	paletteWidth    = 56
+)
+
+func (m *Model) View() string {
+	styles := shared.Styles()
+
+	lines := []string{m.input.View(), ""}
+
+	start := m.cursor - visibleCommands + 1
+	if start < 0 {
+		start = 0
+	}
+	end := start + visibleCommands
+	if end > len(m.matches) {
+		end = len(m.matches)
+	}
+
+	if len(m.matches) == 0 {
+		lines = append(lines, styles.SubText.Render("no matching commands"))
+	}
+	for i := start; i < end; i++ {
+		lines = append(lines, renderMatch(m.matches[i], i == m.cursor))
+	}
+
+	lines = append(lines, shared.HelpLine(m.keys))
+
+	box := styles.ResultsContainer.
+		UnsetMarginTop().
+		Width(paletteWidth).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.Place(
+		m.windowWidth,
+		m.windowHeight,
+		lipgloss.Center,
+		lipgloss.Center,
+		box,
+	)
+}
+
+// renderMatch highlights the characters the fuzzy query matched.
+func renderMatch(mt match, selected bool) string {
+	styles := shared.Styles()
+
+	base := styles.Text
+	prefix := "  "
+	if selected {
+		base = styles.Selected
+		prefix = "> "
+	}
+
+	matched := make(map[int]bool, len(mt.indexes))
+	for _, i := range mt.indexes {
+		matched[i] = true
+	}
+
+	var b strings.Builder
+	b.WriteString(base.Render(prefix))
+	for i, r := range mt.command.label() {
+		if matched[i] {
+			b.WriteString(styles.StatValue.Underline(true).Render(string(r)))
+		} else {
+			b.WriteString(base.Render(string(r)))
+		}
+	}
+	if mt.command.Active {
+		b.WriteString(styles.SubText.Render(" ✓"))
+	}
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package quotes
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+
+	"aiotype/internal/quote"
+	"aiotype/internal/ui/keymap"
+)
+
+// Model searches the quotes of one language by text, source or ID.
+type Model struct {
+	keys         keymap.QuotesKeyMap
+	input        textinput.Model
+	corpus       *quote.Corpus
+	language     string
+	favorites    map[string]bool
+	ratings      map[string]int
+	matches      []quote.Quote
+	err          error
+	cursor       int
+	windowWidth  int
+	windowHeight int
+}
+
+func NewModel(keys keymap.QuotesKeyMap, corpus *quote.Corpus) *Model {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "search text or source, or #id"
+	input.CharLimit = 64
+	input.Width = listWidth - 4
+
+	return &Model{
+		keys:   keys,
+		input:  input,
+		corpus: corpus,
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.windowWidth = msg.Width
+		m.windowHeight = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Down):
+			if m.cursor < len(m.matches)-1 {
+				m.cursor++
+			}
+			return m, nil
+		}
+	}
+
+	previous := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != previous {
+		m.search()
+	}
+	return m, cmd
+}
+
+// Open lists every quote of language with an empty query.
+func (m *Model) Open(language string) tea.Cmd {
+	m.language = language
+	m.input.SetValue("")
+	m.search()
+	return m.input.Focus()
+}
+
+func (m *Model) Close() {
+	m.input.Blur()
+}
+
+// SetMarks shows which quotes are favorites and how they were rated,
+// keyed by quote.Quote.Key.
+func (m *Model) SetMarks(favorites []string, ratings map[string]int) {
+	m.favorites = make(map[string]bool, len(favorites))
+	for _, k := range favorites {
+		m.favorites[k] = true
+	}
+	m.ratings = ratings
+}
+
+func (m *Model) Selected() (quote.Quote, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.matches) {
+		return quote.Quote{}, false
+	}
+	return m.matches[m.cursor], true
+}
+
+func (m *Model) search() {
+	m.cursor = 0
+	m.matches, m.err = m.corpus.Search(m.language, m.input.Value())
+}
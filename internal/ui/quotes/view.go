@@ -0,0 +1,84 @@
+package quotes
+
+import (
+	"fmt"
+	"strings"
+
+	"aiotype/internal/ui/shared"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	visibleQuotes = 10
+	listWidth     = 72
+)
+
+func (m *Model) View() string {
+	styles := shared.Styles()
+
+	lines := []string{
+		styles.Title.UnsetMarginBottom().Render("Quotes") + styles.SubText.Render(" · "+m.language),
+		m.input.View(),
+		"",
+	}
+
+	start := m.cursor - visibleQuotes + 1
+	if start < 0 {
+		start = 0
+	}
+	end := start + visibleQuotes
+	if end > len(m.matches) {
+		end = len(m.matches)
+	}
+
+	switch {
+	case m.err != nil:
+		lines = append(lines, styles.ErrorText.Render(m.err.Error()))
+	case len(m.matches) == 0:
+		lines = append(lines, styles.SubText.Render("no matching quotes"))
+	}
+	for i := start; i < end; i++ {
+		lines = append(lines, m.renderQuote(i))
+	}
+
+	lines = append(lines, "", shared.HelpLine(m.keys))
+
+	box := styles.ResultsContainer.
+		UnsetMarginTop().
+		Width(listWidth).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.Place(
+		m.windowWidth,
+		m.windowHeight,
+		lipgloss.Center,
+		lipgloss.Center,
+		box,
+	)
+}
+
+// renderQuote draws one row: ID, length, favorite star and rating, then
+// as much of the text as fits.
+func (m *Model) renderQuote(i int) string {
+	styles := shared.Styles()
+	q := m.matches[i]
+
+	marks := " "
+	if m.favorites[q.Key()] {
+		marks = "★"
+	}
+	if rating := m.ratings[q.Key()]; rating > 0 {
+		marks += fmt.Sprintf(" %d/5", rating)
+	} else {
+		marks += "    "
+	}
+	meta := fmt.Sprintf("#%-3d %-6s %s ", q.ID, q.Length(), marks)
+
+	base, prefix := styles.Text, "  "
+	if i == m.cursor {
+		base, prefix = styles.Selected, "> "
+	}
+	text := ansi.Truncate(q.Text, listWidth-4-len(prefix)-len([]rune(meta)), "…")
+	return base.Render(prefix) + styles.SubText.Render(meta) + base.Render(text)
+}
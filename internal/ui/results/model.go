@@ -2,17 +2,28 @@ package results
 
 import (
 	"aiotype/internal"
+	"aiotype/internal/ui/keymap"
 	"github.com/charmbracelet/bubbletea"
 )
 
 type Model struct {
-	result       *internal.TestResult
+	keys   keymap.ResultsKeyMap
+	result *internal.TestResult
+	status string
+	// favorite and rating are the marks on the quote of the result.
+	favorite bool
+	rating   int
+	// note is an extra row under label, e.g. what the next weakness
+	// practice targets or a letter lesson mode unlocked.
+	noteLabel    string
+	note         string
 	windowWidth  int
 	windowHeight int
 }
 
-func NewModel(result *internal.TestResult) *Model {
+func NewModel(keys keymap.ResultsKeyMap, result *internal.TestResult) *Model {
 	return &Model{
+		keys:   keys,
 		result: result,
 	}
 }
@@ -33,4 +44,19 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *Model) SetResult(result *internal.TestResult) {
 	m.result = result
+	m.status = ""
+	m.noteLabel, m.note = "", ""
+}
+
+func (m *Model) SetQuoteMarks(favorite bool, rating int) {
+	m.favorite = favorite
+	m.rating = rating
+}
+
+func (m *Model) SetNote(label, note string) {
+	m.noteLabel, m.note = label, note
+}
+
+func (m *Model) SetStatus(status string) {
+	m.status = status
 }
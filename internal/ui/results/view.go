@@ -2,6 +2,7 @@ package results
 
 import (
 	"fmt"
+	"strings"
 
 	"aiotype/internal/ui/shared"
 	"github.com/charmbracelet/lipgloss"
@@ -12,18 +13,72 @@ func (m *Model) View() string {
 		return ""
 	}
 
-	title := shared.ResultTitleStyle.Render("🎉 Test Complete!")
+	styles := shared.Styles()
+	title := styles.ResultTitle.Render("🎉 Test Complete!")
 
 	stats := []string{
-		fmt.Sprintf("%s %s", shared.StatLabelStyle.Render("WPM:"), shared.StatValueStyle.Render(fmt.Sprintf("%.1f", m.result.WPM))),
-		fmt.Sprintf("%s %s", shared.StatLabelStyle.Render("Accuracy:"), shared.StatValueStyle.Render(fmt.Sprintf("%.1f%%", m.result.Accuracy))),
-		fmt.Sprintf("%s %s", shared.StatLabelStyle.Render("Words:"), shared.StatValueStyle.Render(fmt.Sprintf("%d/%d", m.result.CorrectWords, m.result.TotalWords))),
-		fmt.Sprintf("%s %s", shared.StatLabelStyle.Render("Characters:"), shared.StatValueStyle.Render(fmt.Sprintf("%d/%d", m.result.CorrectChars, m.result.TotalChars))),
-		fmt.Sprintf("%s %s", shared.StatLabelStyle.Render("Errors:"), shared.StatValueStyle.Render(fmt.Sprintf("%d", m.result.ErrorCount))),
+		fmt.Sprintf("%s %s", styles.StatLabel.Render("WPM:"), styles.StatValue.Render(fmt.Sprintf("%.1f", m.result.WPM))),
+		fmt.Sprintf("%s %s", styles.StatLabel.Render("Raw:"), styles.StatValue.Render(fmt.Sprintf("%.1f", m.result.RawWPM))),
+		fmt.Sprintf("%s %s", styles.StatLabel.Render("Accuracy:"), styles.StatValue.Render(fmt.Sprintf("%.1f%%", m.result.Accuracy))),
+		fmt.Sprintf("%s %s", styles.StatLabel.Render("Words:"), styles.StatValue.Render(fmt.Sprintf("%d/%d", m.result.CorrectWords, m.result.TotalWords))),
+		fmt.Sprintf("%s %s", styles.StatLabel.Render("Characters:"), styles.StatValue.Render(fmt.Sprintf("%d/%d", m.result.CorrectChars, m.result.TotalChars))),
+		fmt.Sprintf("%s %s", styles.StatLabel.Render("Errors:"), styles.StatValue.Render(fmt.Sprintf("%d", m.result.ErrorCount))),
+	}
+
+	if m.result.Pauses > 0 {
+		stats = append(stats, fmt.Sprintf("%s %s", styles.StatLabel.Render("Paused:"),
+			styles.StatValue.Render(fmt.Sprintf("%d× (%.1fs, not timed)", m.result.Pauses, m.result.PausedDuration.Seconds()))))
+	}
+
+	if len(m.result.Funboxes) > 0 {
+		stats = append(stats, fmt.Sprintf("%s %s", styles.StatLabel.Render("Funbox:"),
+			styles.StatValue.Render(strings.Join(m.result.Funboxes, ", "))))
+	}
+
+	if lenient := m.result.Lenient; lenient.Enabled() {
+		var rules []string
+		if lenient.Fold {
+			rules = append(rules, "accents")
+		}
+		if lenient.IgnoreCase {
+			rules = append(rules, "case")
+		}
+		stats = append(stats, fmt.Sprintf("%s %s", styles.StatLabel.Render("Lenient:"),
+			styles.StatValue.Render(strings.Join(rules, ", ")+" (ranked apart)")))
+	}
+
+	if q := m.result.Quote; q != nil {
+		marks := fmt.Sprintf("#%d %s", q.ID, q.Language)
+		if m.favorite {
+			marks += " ★"
+		}
+		if m.rating > 0 {
+			marks += fmt.Sprintf(" • rated %d/5", m.rating)
+		}
+		stats = append(stats,
+			fmt.Sprintf("%s %s", styles.StatLabel.Render("Quote:"), styles.StatValue.Render(marks)),
+			fmt.Sprintf("%s %s", styles.StatLabel.Render("Source:"), styles.StatValue.Render(q.Source)),
+		)
+	}
+
+	if m.note != "" {
+		stats = append(stats, fmt.Sprintf("%s %s", styles.StatLabel.Render(m.noteLabel), styles.StatValue.Render(m.note)))
 	}
 
 	statsDisplay := lipgloss.JoinVertical(lipgloss.Left, stats...)
-	help := shared.HelpStyle.Render("ENTER/R to restart • ESC for menu • Q to quit")
+	help := shared.HelpLine(m.keys)
+	if m.status != "" {
+		help = lipgloss.JoinVertical(lipgloss.Center, styles.SubText.Render(m.status), help)
+	}
+
+	if m.result.Invalid != "" {
+		title = lipgloss.JoinVertical(
+			lipgloss.Center,
+			title,
+			styles.ErrorText.Render("Invalid test: "+m.result.Invalid),
+			styles.SubText.Render("saved, but not counted in PBs or averages"),
+		)
+	}
 
 	content := lipgloss.JoinVertical(
 		lipgloss.Center,
@@ -34,7 +89,7 @@ func (m *Model) View() string {
 		help,
 	)
 
-	container := shared.ResultsContainerStyle.Width(50).Render(content)
+	container := styles.ResultsContainer.Width(50).Render(content)
 
 	return lipgloss.Place(
 		m.windowWidth,
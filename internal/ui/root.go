@@ -1,50 +1,318 @@
 package ui
 
 import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbletea"
 
 	"aiotype/internal"
+	"aiotype/internal/config"
+	"aiotype/internal/curriculum"
+	"aiotype/internal/funbox"
+	"aiotype/internal/history"
+	"aiotype/internal/lesson"
+	"aiotype/internal/markov"
+	"aiotype/internal/quote"
+	"aiotype/internal/theme"
+	"aiotype/internal/ui/failed"
+	"aiotype/internal/ui/funboxes"
+	"aiotype/internal/ui/keymap"
+	"aiotype/internal/ui/lessonmap"
 	"aiotype/internal/ui/menu"
+	"aiotype/internal/ui/palette"
+	"aiotype/internal/ui/quotes"
 	"aiotype/internal/ui/results"
+	"aiotype/internal/ui/shared"
+	"aiotype/internal/ui/themes"
 	"aiotype/internal/ui/typing"
+	"aiotype/internal/weakness"
+)
+
+type pendingAction int
+
+const (
+	actionNone pendingAction = iota
+	actionMenu
+	actionRestart
+	actionRepeat
 )
 
+func (a pendingAction) prompt() string {
+	switch a {
+	case actionMenu:
+		return "Leave this test? Press again or ENTER to confirm • any other key to keep typing"
+	case actionRestart:
+		return "Restart with new words? Press again or ENTER to confirm • any other key to keep typing"
+	case actionRepeat:
+		return "Repeat these words? Press again or ENTER to confirm • any other key to keep typing"
+	}
+	return ""
+}
+
 type Model struct {
-	state        internal.GameState
-	config       internal.GameConfig
-	menuModel    *menu.Model
-	typingModel  *typing.Model
-	resultsModel *results.Model
+	state    internal.GameState
+	config   internal.GameConfig
+	settings config.Config
+	store    *history.Store
+	records  []history.Record
+	lessons  *lesson.Store
+	progress *lesson.Progress
+	// lessonTargets are what every letter must reach in lesson mode.
+	lessonTargets  lesson.Targets
+	course         *curriculum.Curriculum
+	courses        *curriculum.Store
+	courseProgress *curriculum.Progress
+	// activeLesson is the curriculum lesson being practiced, nil outside
+	// the curriculum. Restarting gives a fresh exercise of it.
+	activeLesson *curriculum.Lesson
+	registry     *theme.Registry
+	quotes       *quote.Corpus
+	mode         internal.Mode
+	// markov generates the text of words mode when it is not random
+	// words. It is built in the background from generatorSettings, and
+	// generatorStale asks for a rebuild after they change.
+	markov            *markov.Generator
+	generatorSettings config.Generator
+	generatorStale    bool
+	paceMode          typing.PaceMode
+	pending           pendingAction
+	keys              keymap.KeyMap
+	showHelp          bool
+	showPalette       bool
+	lastResult        *internal.TestResult
+	missedWords       []string
+	windowWidth       int
+	windowHeight      int
+	menuModel         *menu.Model
+	typingModel       *typing.Model
+	resultsModel      *results.Model
+	failedModel       *failed.Model
+	themesModel       *themes.Model
+	funboxModel       *funboxes.Model
+	quotesModel       *quotes.Model
+	lessonMapModel    *lessonmap.Model
+	paletteModel      *palette.Model
+	// clipboard is an OSC 52 copy written after the view until it has
+	// been drawn.
+	clipboard string
 }
 
-func NewModel() *Model {
-	config := internal.DefaultGameConfig()
+func NewModel(settings config.Config, registry *theme.Registry, store *history.Store, lessons *lesson.Store, courses *curriculum.Store) *Model {
+	gameConfig := internal.DefaultGameConfig()
+
+	keys := keymap.Default()
+	keysErr := keys.Apply(settings.Keys)
+
+	var records []history.Record
+	var historyErr error
+	if store != nil {
+		records, historyErr = store.Load()
+	}
+	corpus, quotesErr := quote.Load()
+	progress := lesson.NewProgress()
+	var lessonErr error
+	if lessons != nil {
+		progress, lessonErr = lessons.Load()
+	}
+	course, courseErr := curriculum.Load()
+	courseProgress := curriculum.NewProgress()
+	var courseProgressErr error
+	if courses != nil {
+		courseProgress, courseProgressErr = courses.Load()
+	}
+
+	m := &Model{
+		state:          internal.StateMenu,
+		config:         gameConfig,
+		settings:       settings,
+		store:          store,
+		records:        records,
+		lessons:        lessons,
+		progress:       progress,
+		lessonTargets:  lesson.DefaultTargets(),
+		course:         course,
+		courses:        courses,
+		courseProgress: courseProgress,
+		registry:       registry,
+		quotes:         corpus,
+		keys:           keys,
+		menuModel:      menu.NewModel(keys.Menu),
+		typingModel:    typing.NewModel(keys.Typing, gameConfig),
+		resultsModel:   results.NewModel(keys.Results, nil),
+		failedModel:    failed.NewModel(keys.Results),
+		themesModel:    themes.NewModel(keys.Themes, registry),
+		funboxModel:    funboxes.NewModel(keys.Funbox),
+		quotesModel:    quotes.NewModel(keys.Quotes, corpus),
+		lessonMapModel: lessonmap.NewModel(keys.LessonMap, course),
+		paletteModel:   palette.NewModel(keys.Palette),
+	}
 
-	return &Model{
-		state:        internal.StateMenu,
-		config:       config,
-		menuModel:    menu.NewModel(),
-		typingModel:  typing.NewModel(config),
-		resultsModel: results.NewModel(nil),
+	if err := errors.Join(keysErr, m.applySettings(), historyErr, lessonErr, courseErr, courseProgressErr, quotesErr); err != nil {
+		m.menuModel.SetStatus(err.Error())
 	}
+
+	return m
+}
+
+// applySettings pushes the test and typing settings into the models. It
+// runs at startup and whenever the command palette changes a setting.
+func (m *Model) applySettings() error {
+	if m.settings.Words > 0 {
+		m.config.WordCount = m.settings.Words
+	}
+	mode, modeErr := internal.ParseMode(m.settings.Mode)
+	m.mode = mode
+	m.config.Mode = mode
+	targets := lesson.Targets{WPM: m.settings.Lesson.TargetWPM, Accuracy: m.settings.Lesson.TargetAccuracy}
+	targetsErr := targets.Validate()
+	if targetsErr == nil {
+		m.lessonTargets = targets
+	}
+	if mode == internal.ModeLesson {
+		m.typingModel.SetLesson(m.progress, m.lessonTargets)
+	} else {
+		m.typingModel.SetLesson(nil, m.lessonTargets)
+	}
+	_, lengthErr := quote.ParseLength(m.settings.Quote.Length)
+	m.config.FreedomMode = m.settings.Input.Freedom
+	difficulty, difficultyErr := internal.ParseDifficulty(m.settings.Difficulty)
+	m.config.Difficulty = difficulty
+	stopOnError, stopErr := internal.ParseStopOnError(m.settings.Input.StopOnError)
+	m.config.StopOnError = stopOnError
+	m.config.Lenient = internal.Lenient{
+		Fold:       m.settings.Input.Lenient,
+		IgnoreCase: m.settings.Input.IgnoreCase,
+	}
+	m.config.Minimums = internal.Minimums{
+		WPM:      m.settings.Minimum.WPM,
+		Accuracy: m.settings.Minimum.Accuracy,
+		Burst:    m.settings.Minimum.Burst,
+		Grace:    time.Duration(m.settings.Minimum.GraceSeconds) * time.Second,
+	}
+	funboxes, funboxErr := funbox.Parse(m.settings.Funbox)
+	m.config.NoSpace = funboxes.NoSpace()
+	m.config.Funboxes = funboxes.Names()
+	m.typingModel.SetFunboxes(funboxes)
+	selection := internal.WordSelection{
+		TopN:   m.settings.Generator.TopN,
+		Zipf:   m.settings.Generator.Zipf,
+		Window: m.settings.Generator.RepeatWindow,
+	}
+	selectionErr := selection.Validate()
+	if selectionErr == nil {
+		m.config.Selection = selection
+	}
+	if !sameGenerator(m.settings.Generator, m.generatorSettings) {
+		m.generatorSettings = m.settings.Generator
+		m.generatorSettings.Files = slices.Clone(m.settings.Generator.Files)
+		m.generatorStale = true
+		m.markov = nil
+	}
+	m.typingModel.SetGameConfig(m.config)
+	m.typingModel.SetVisibleLines(m.settings.VisibleLines)
+	m.typingModel.SetValidityRules(validityRules(m.settings.Validity))
+
+	layout, layoutErr := typing.ParseLayout(m.settings.Layout)
+	tapeScroll, scrollErr := typing.ParseTapeScroll(m.settings.Tape.Scroll)
+	m.typingModel.SetLayout(layout, tapeScroll, m.settings.Tape.CaretColumn)
+
+	caretStyle, caretErr := typing.ParseCaretStyle(m.settings.Caret.Style)
+	m.typingModel.SetCaret(caretStyle, m.settings.Caret.Blink)
+
+	pasteMode, pasteErr := typing.ParsePasteMode(m.settings.Input.Paste)
+	m.typingModel.SetPasteMode(pasteMode)
+	m.typingModel.SetBlind(m.settings.Input.Blind)
+
+	paceMode, paceErr := typing.ParsePaceMode(m.settings.Caret.Pace)
+	m.paceMode = paceMode
+	if m.state == internal.StateTyping {
+		m.setPace()
+	}
+
+	return errors.Join(modeErr, lengthErr, selectionErr, targetsErr, difficultyErr, funboxErr, stopErr, layoutErr, scrollErr, caretErr, pasteErr, paceErr)
 }
 
 func (m *Model) Init() tea.Cmd {
-	return nil
+	return m.reloadGenerator()
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if windowMsg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.windowWidth = windowMsg.Width
+		m.windowHeight = windowMsg.Height
 		m.menuModel.Update(windowMsg)
 		m.typingModel.Update(windowMsg)
 		m.resultsModel.Update(windowMsg)
+		m.failedModel.Update(windowMsg)
+		m.themesModel.Update(windowMsg)
+		m.funboxModel.Update(windowMsg)
+		m.quotesModel.Update(windowMsg)
+		m.lessonMapModel.Update(windowMsg)
+		m.paletteModel.Update(windowMsg)
+	}
+
+	if _, ok := msg.(tea.BlurMsg); ok {
+		if m.state == internal.StateTyping && m.settings.Pause.OnBlur {
+			m.typingModel.Pause()
+		}
+		return m, nil
 	}
 
-	if key, ok := msg.(tea.KeyMsg); ok {
-		switch key.String() {
-		case "ctrl+c":
+	if loaded, ok := msg.(generatorLoadedMsg); ok {
+		// A newer change may already have asked for another generator.
+		if sameGenerator(loaded.settings, m.generatorSettings) {
+			m.markov = loaded.generator
+		}
+		if loaded.err != nil {
+			m.menuModel.SetStatus(loaded.err.Error())
+		}
+		return m, nil
+	}
+
+	if sent, ok := msg.(clipboardSentMsg); ok {
+		// A later copy may have replaced this one.
+		if m.clipboard == sent.sequence {
+			m.clipboard = ""
+		}
+		return m, nil
+	}
+
+	if errMsg, ok := msg.(shared.ErrMsg); ok {
+		m.menuModel.SetStatus(errMsg.Error())
+		return m, nil
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if key.Matches(keyMsg, m.keys.Global.ForceQuit) {
 			return m, tea.Quit
 		}
+		if m.showPalette {
+			return m.updatePalette(keyMsg)
+		}
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		if key.Matches(keyMsg, m.keys.Global.Palette) {
+			// Time spent in the palette should not count against the test.
+			if m.state == internal.StateTyping {
+				m.typingModel.Pause()
+			}
+			m.showPalette = true
+			return m, m.paletteModel.Open(m.commands())
+		}
+		if key.Matches(keyMsg, m.helpBinding()) {
+			m.showHelp = true
+			return m, nil
+		}
 	}
 
 	switch m.state {
@@ -52,8 +320,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateMenu(msg)
 	case internal.StateTyping:
 		return m.updateTyping(msg)
-	case internal.StateResults:
+	case internal.StateResults, internal.StateFailed:
 		return m.updateResults(msg)
+	case internal.StateThemes:
+		return m.updateThemes(msg)
+	case internal.StateFunbox:
+		return m.updateFunbox(msg)
+	case internal.StateQuotes:
+		return m.updateQuotes(msg)
+	case internal.StateLessonMap:
+		return m.updateLessonMap(msg)
 	}
 
 	return m, nil
@@ -62,14 +338,25 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *Model) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
 	_, cmd := m.menuModel.Update(msg)
 
-	if key, ok := msg.(tea.KeyMsg); ok {
-		switch key.String() {
-		case "q":
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, m.keys.Menu.Quit):
 			return m, tea.Quit
-		case "enter", " ":
-			m.state = internal.StateTyping
-			m.typingModel.Reset()
-			return m, m.typingModel.Init()
+		case key.Matches(keyMsg, m.keys.Menu.Start):
+			m.activeLesson = nil
+			return m, m.startTest()
+		case key.Matches(keyMsg, m.keys.Menu.Lessons):
+			return m, m.openLessonMap()
+		case key.Matches(keyMsg, m.keys.Menu.Themes):
+			m.themesModel.Open()
+			m.state = internal.StateThemes
+			return m, nil
+		case key.Matches(keyMsg, m.keys.Menu.Quotes):
+			return m, m.openQuotes()
+		case key.Matches(keyMsg, m.keys.Menu.Funbox):
+			m.funboxModel.Open(m.settings.Funbox)
+			m.state = internal.StateFunbox
+			return m, nil
 		}
 	}
 
@@ -77,10 +364,28 @@ func (m *Model) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) updateTyping(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if key, ok := msg.(tea.KeyMsg); ok {
-		if key.String() == "esc" {
-			m.state = internal.StateMenu
-			return m, nil
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if m.pending != actionNone {
+			return m, m.resolvePending(keyMsg)
+		}
+
+		action := actionNone
+		switch {
+		case key.Matches(keyMsg, m.keys.Typing.Menu):
+			action = actionMenu
+		case key.Matches(keyMsg, m.keys.Typing.Restart):
+			action = actionRestart
+		case key.Matches(keyMsg, m.keys.Typing.Repeat):
+			action = actionRepeat
+		}
+
+		if action != actionNone {
+			if m.needsConfirmation() {
+				m.pending = action
+				m.typingModel.SetPrompt(action.prompt())
+				return m, nil
+			}
+			return m, m.runAction(action)
 		}
 	}
 
@@ -88,35 +393,597 @@ func (m *Model) updateTyping(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	if m.typingModel.IsCompleted() {
 		result := m.typingModel.GetResult()
-		m.resultsModel.SetResult(result)
-		m.state = internal.StateResults
-		return m, nil
+		m.lastResult = result
+		m.missedWords = m.typingModel.MissedWords()
+		if result.Failed != "" {
+			m.failedModel.SetResult(result)
+			m.state = internal.StateFailed
+		} else {
+			m.resultsModel.SetResult(result)
+			m.showQuoteMarks()
+			m.state = internal.StateResults
+		}
+		cmd := m.recordResult(result)
+		if result.Failed != "" {
+			m.countFailures()
+		}
+		// Notes and progress follow the test that ran, which need not be
+		// of the configured mode, e.g. a quote picked from the quotes
+		// screen.
+		switch result.Mode {
+		case internal.ModeExercise:
+			cmd = tea.Batch(cmd, m.recordExercise(result))
+		case internal.ModeWeakness:
+			m.resultsModel.SetNote("Next focus:", weaknessSummary(weakness.Analyze(m.records)))
+		case internal.ModeLesson:
+			cmd = tea.Batch(cmd, m.recordLesson(result))
+		}
+		return m, cmd
 	}
 
 	return m, cmd
 }
 
+func (m *Model) needsConfirmation() bool {
+	confirmAfter := time.Duration(m.settings.QuickRestart.ConfirmAfter) * time.Second
+	return confirmAfter > 0 && m.typingModel.Elapsed() >= confirmAfter
+}
+
+// resolvePending confirms the pending action when its key is pressed
+// again, or with the confirm key. Any other key cancels it and is not typed.
+func (m *Model) resolvePending(keyMsg tea.KeyMsg) tea.Cmd {
+	action := m.pending
+	m.pending = actionNone
+	m.typingModel.SetPrompt("")
+
+	confirmed := key.Matches(keyMsg, m.keys.Typing.Confirm) ||
+		(action == actionMenu && key.Matches(keyMsg, m.keys.Typing.Menu)) ||
+		(action == actionRestart && key.Matches(keyMsg, m.keys.Typing.Restart)) ||
+		(action == actionRepeat && key.Matches(keyMsg, m.keys.Typing.Repeat))
+	if !confirmed {
+		return nil
+	}
+	return m.runAction(action)
+}
+
+func (m *Model) runAction(action pendingAction) tea.Cmd {
+	switch action {
+	case actionMenu:
+		m.state = internal.StateMenu
+		return nil
+	case actionRestart:
+		return m.startTest()
+	case actionRepeat:
+		return m.repeatTest()
+	}
+	return nil
+}
+
+func (m *Model) repeatTest() tea.Cmd {
+	m.state = internal.StateTyping
+	m.typingModel.Repeat()
+	m.setPace()
+	return m.typingModel.Init()
+}
+
+func (m *Model) practiceMissedWords() tea.Cmd {
+	m.state = internal.StateTyping
+	m.typingModel.Practice(m.missedWords)
+	m.setPace()
+	return m.typingModel.Init()
+}
+
+func (m *Model) startTest() tea.Cmd {
+	if m.activeLesson != nil {
+		return m.startExercise(*m.activeLesson)
+	}
+	if m.mode == internal.ModeQuote {
+		q, err := m.pickQuote()
+		if err != nil {
+			m.state = internal.StateMenu
+			m.menuModel.SetStatus(err.Error())
+			return nil
+		}
+		return m.startQuote(q)
+	}
+	m.state = internal.StateTyping
+	m.typingModel.SetGenerator(m.wordGenerator())
+	m.typingModel.Reset()
+	m.setPace()
+	return m.typingModel.Init()
+}
+
+// recordLesson updates lesson progress from a finished test and saves
+// it. Failed and invalid tests do not count.
+func (m *Model) recordLesson(result *internal.TestResult) tea.Cmd {
+	if result.Failed != "" || result.Invalid != "" {
+		return nil
+	}
+	unlocked := m.progress.Record(m.typingModel.KeyTimings(), m.lessonTargets)
+	note := fmt.Sprintf("%d/%d letters", len(m.progress.Letters()), len(lesson.Alphabet))
+	if unlocked != 0 {
+		note = fmt.Sprintf("unlocked %c! • %s", unlocked, note)
+	} else if focus := m.progress.Focus(m.lessonTargets); focus != 0 {
+		note += fmt.Sprintf(" • focus %c", focus)
+	}
+	m.resultsModel.SetNote("Lesson:", note)
+
+	if m.lessons == nil {
+		return nil
+	}
+	store, progress := m.lessons, m.progress.Clone()
+	return func() tea.Msg {
+		if err := store.Save(progress); err != nil {
+			return shared.ErrMsg{Err: err}
+		}
+		return nil
+	}
+}
+
+// recordExercise checks a finished exercise against its lesson's pass
+// criteria and saves the curriculum progress.
+func (m *Model) recordExercise(result *internal.TestResult) tea.Cmd {
+	l, err := m.course.Find(result.Exercise)
+	if err != nil {
+		return nil
+	}
+	passed, unlocked := m.course.Record(m.courseProgress, l, result)
+	note := fmt.Sprintf("%s needs %.0f wpm and %.0f%%", l.Title, l.PassWPM, l.PassAccuracy)
+	if passed {
+		note = l.Title + " passed!"
+		for _, u := range unlocked {
+			note += " • unlocked " + u.Title
+		}
+	}
+	m.resultsModel.SetNote("Lesson:", note)
+
+	if m.courses == nil {
+		return nil
+	}
+	store, progress := m.courses, m.courseProgress.Clone()
+	return func() tea.Msg {
+		if err := store.Save(progress); err != nil {
+			return shared.ErrMsg{Err: err}
+		}
+		return nil
+	}
+}
+
+func weaknessSummary(profile weakness.Profile) string {
+	if profile.Empty() {
+		return "nothing yet, keep typing"
+	}
+	return profile.String()
+}
+
+// wordGenerator makes the words of the next words or weakness test, nil
+// for random words. Weakness practice reads the history afresh each
+// time, so it follows the typist as they improve.
+func (m *Model) wordGenerator() typing.Generator {
+	switch {
+	case m.mode == internal.ModeWeakness:
+		profile := weakness.Analyze(m.records)
+		return weakness.NewGenerator(profile, internal.RankedWords(), m.config.Selection.Window)
+	case m.mode == internal.ModeLesson:
+		return lesson.NewGenerator(m.progress.Letters(), m.progress.Focus(m.lessonTargets))
+	case m.markov != nil:
+		return m.markov
+	}
+	return nil
+}
+
+// startExercise starts a fresh exercise of a curriculum lesson.
+func (m *Model) startExercise(l curriculum.Lesson) tea.Cmd {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	if !m.typingModel.StartExercise(l.ID, l.Exercise(rng)) {
+		m.activeLesson = nil
+		m.openLessonMap()
+		m.lessonMapModel.SetStatus(l.Title + " has no words to type")
+		return nil
+	}
+	m.activeLesson = &l
+	m.state = internal.StateTyping
+	m.setPace()
+	return m.typingModel.Init()
+}
+
+func (m *Model) openLessonMap() tea.Cmd {
+	m.lessonMapModel.Open(m.courseProgress)
+	m.state = internal.StateLessonMap
+	return nil
+}
+
+func (m *Model) startQuote(q quote.Quote) tea.Cmd {
+	m.activeLesson = nil
+	m.state = internal.StateTyping
+	m.typingModel.StartQuote(q)
+	m.setPace()
+	return m.typingModel.Init()
+}
+
+// pickQuote draws a quote matching the quote settings.
+func (m *Model) pickQuote() (quote.Quote, error) {
+	length, _ := quote.ParseLength(m.settings.Quote.Length)
+	all, err := m.quotes.Quotes(m.settings.Quote.Language)
+	if err != nil {
+		return quote.Quote{}, err
+	}
+
+	var keep func(quote.Quote) bool
+	if m.settings.Quote.FavoritesOnly {
+		keep = func(q quote.Quote) bool {
+			return slices.Contains(m.settings.Quote.Favorites, q.Key())
+		}
+	}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	q, ok := quote.Pick(all, length, keep, rng)
+	if !ok {
+		pool := "quotes"
+		if keep != nil {
+			pool = "favorite quotes"
+		}
+		return quote.Quote{}, fmt.Errorf("%w: no %s %s %s", quote.ErrNotFound, length, m.settings.Quote.Language, pool)
+	}
+	return q, nil
+}
+
+func (m *Model) openQuotes() tea.Cmd {
+	m.quotesModel.SetMarks(m.settings.Quote.Favorites, m.settings.Quote.Ratings)
+	m.state = internal.StateQuotes
+	return m.quotesModel.Open(m.settings.Quote.Language)
+}
+
+// toggleFavorite adds or removes the quote with key from the favorites.
+func (m *Model) toggleFavorite(key string) tea.Cmd {
+	favorites := m.settings.Quote.Favorites
+	if i := slices.Index(favorites, key); i >= 0 {
+		m.settings.Quote.Favorites = slices.Delete(favorites, i, i+1)
+	} else {
+		m.settings.Quote.Favorites = append(favorites, key)
+	}
+	return saveSettings(m.settings)
+}
+
+func (m *Model) rateQuote(key string, rating int) tea.Cmd {
+	if m.settings.Quote.Ratings == nil {
+		m.settings.Quote.Ratings = map[string]int{}
+	}
+	m.settings.Quote.Ratings[key] = rating
+	return saveSettings(m.settings)
+}
+
+// lastQuoteKey is the key of the quote of the last result, if it was one.
+func (m *Model) lastQuoteKey() (string, bool) {
+	if m.lastResult == nil || m.lastResult.Quote == nil {
+		return "", false
+	}
+	q := quote.Quote{ID: m.lastResult.Quote.ID, Language: m.lastResult.Quote.Language}
+	return q.Key(), true
+}
+
+func (m *Model) showQuoteMarks() {
+	if key, ok := m.lastQuoteKey(); ok {
+		m.resultsModel.SetQuoteMarks(slices.Contains(m.settings.Quote.Favorites, key), m.settings.Quote.Ratings[key])
+	}
+}
+
+// setPace points the pace caret at the current test, which it compares
+// with earlier tests of the same mode.
+func (m *Model) setPace() {
+	mode := m.typingModel.Mode()
+	switch m.paceMode {
+	case typing.PaceWPM:
+		m.typingModel.SetPaceWPM(m.settings.Caret.PaceWPM)
+	case typing.PacePB:
+		m.typingModel.SetPaceWPM(history.PersonalBest(m.rankedRecords(mode)))
+	case typing.PaceAverage:
+		m.typingModel.SetPaceWPM(history.AverageWPM(m.rankedRecords(mode), history.AverageWindow))
+	default:
+		m.typingModel.SetPaceWPM(0)
+	}
+}
+
+func validityRules(cfg config.Validity) internal.ValidityRules {
+	return internal.ValidityRules{
+		AFKGap:          time.Duration(cfg.AFKSeconds) * time.Second,
+		MinAccuracy:     cfg.MinAccuracy,
+		MaxRawRatio:     cfg.MaxRawRatio,
+		MinTimingSpread: cfg.MinTimingSpread,
+	}
+}
+
+type generatorLoadedMsg struct {
+	settings  config.Generator
+	generator *markov.Generator
+	err       error
+}
+
+// reloadGenerator rebuilds the words mode generator after its settings
+// changed. Reading the files and training happen in the background.
+func (m *Model) reloadGenerator() tea.Cmd {
+	if !m.generatorStale {
+		return nil
+	}
+	m.generatorStale = false
+	settings := m.generatorSettings
+	return func() tea.Msg {
+		wordGenerator, err := generator(settings)
+		return generatorLoadedMsg{settings: settings, generator: wordGenerator, err: err}
+	}
+}
+
+func sameGenerator(a, b config.Generator) bool {
+	return a.Kind == b.Kind && slices.Equal(a.Files, b.Files) && a.Order == b.Order &&
+		a.Coherence == b.Coherence && a.Seed == b.Seed
+}
+
+// generator builds the word generator of words mode, or nil for random
+// common words. Without readable files it trains on the built in prose.
+func generator(cfg config.Generator) (*markov.Generator, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Kind)) {
+	case "", "random":
+		return nil, nil
+	case "markov":
+	default:
+		return nil, fmt.Errorf("%w: %q", markov.ErrInvalidKind, cfg.Kind)
+	}
+
+	texts, filesErr := markov.LoadFiles(cfg.Files)
+	if len(texts) == 0 {
+		texts = []string{markov.Corpus()}
+	}
+	chain, err := markov.Train(texts, cfg.Order)
+	if err != nil {
+		return nil, errors.Join(filesErr, err)
+	}
+	wordGenerator, err := markov.NewGenerator(chain, cfg.Coherence, cfg.Seed)
+	return wordGenerator, errors.Join(filesErr, err)
+}
+
+// rankedRecords are the results of mode that count towards personal
+// bests and averages.
+func (m *Model) rankedRecords(mode internal.Mode) []history.Record {
+	records := history.Lenient(history.Passed(history.Valid(history.OfMode(m.records, mode))), m.config.Lenient.Enabled())
+	if m.settings.Pause.ExcludePaused {
+		records = history.WithoutPaused(records)
+	}
+	return records
+}
+
+// countFailures shows on the failed screen how many tests at the
+// difficulty of the last one failed.
+func (m *Model) countFailures() {
+	if len(m.records) == 0 {
+		return
+	}
+	difficulty := m.records[len(m.records)-1].Difficulty
+	var runs []history.Record
+	for _, record := range m.records {
+		if record.Difficulty == difficulty {
+			runs = append(runs, record)
+		}
+	}
+	m.failedModel.SetFailures(len(history.Failures(runs)), len(runs))
+}
+
+func (m *Model) recordResult(result *internal.TestResult) tea.Cmd {
+	if result == nil {
+		return nil
+	}
+
+	record := history.NewRecord(result)
+	m.records = append(m.records, record)
+
+	if m.store == nil {
+		return nil
+	}
+	store := m.store
+	return func() tea.Msg {
+		if err := store.Append(record); err != nil {
+			return shared.ErrMsg{Err: err}
+		}
+		return nil
+	}
+}
+
 func (m *Model) updateResults(msg tea.Msg) (tea.Model, tea.Cmd) {
 	_, cmd := m.resultsModel.Update(msg)
 
-	if key, ok := msg.(tea.KeyMsg); ok {
-		switch key.String() {
-		case "q":
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, m.keys.Results.Quit):
 			return m, tea.Quit
-		case "enter", " ", "r":
-			m.state = internal.StateTyping
-			m.typingModel.Reset()
-			return m, m.typingModel.Init()
-		case "esc":
+		case key.Matches(keyMsg, m.keys.Results.Restart):
+			return m, m.startTest()
+		case key.Matches(keyMsg, m.keys.Results.Repeat):
+			return m, m.repeatTest()
+		case key.Matches(keyMsg, m.keys.Results.Menu):
+			m.state = internal.StateMenu
+			return m, nil
+		case key.Matches(keyMsg, m.keys.Results.Favorite):
+			if quoteKey, ok := m.lastQuoteKey(); ok {
+				cmd := m.toggleFavorite(quoteKey)
+				m.showQuoteMarks()
+				return m, cmd
+			}
+		case key.Matches(keyMsg, m.keys.Results.Rate):
+			rating, err := strconv.Atoi(keyMsg.String())
+			if quoteKey, ok := m.lastQuoteKey(); ok && err == nil {
+				cmd := m.rateQuote(quoteKey, rating)
+				m.showQuoteMarks()
+				return m, cmd
+			}
+		}
+	}
+
+	return m, cmd
+}
+
+func (m *Model) updateThemes(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, m.keys.Themes.Apply):
+			selected := m.themesModel.Selected()
+			shared.SetTheme(selected)
+			m.settings.Theme = selected.Name
+			m.state = internal.StateMenu
+			return m, saveSettings(m.settings)
+		case key.Matches(keyMsg, m.keys.Themes.Cancel):
+			m.themesModel.Cancel()
+			m.state = internal.StateMenu
+			return m, nil
+		}
+	}
+
+	_, cmd := m.themesModel.Update(msg)
+	return m, cmd
+}
+
+// updateFunbox applies the chosen funboxes when the screen is left, so
+// the next test starts with them.
+func (m *Model) updateFunbox(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, m.keys.Funbox.Done) {
+		m.settings.Funbox = m.funboxModel.Selected()
+		m.state = internal.StateMenu
+		return m, m.settingsChanged(m.applySettings())
+	}
+
+	_, cmd := m.funboxModel.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) updateLessonMap(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, m.keys.LessonMap.Back):
+			m.state = internal.StateMenu
+			return m, nil
+		case key.Matches(keyMsg, m.keys.LessonMap.Start):
+			l, ok := m.lessonMapModel.Selected()
+			if !ok {
+				m.lessonMapModel.SetStatus("locked: pass the lessons before it first")
+				return m, nil
+			}
+			return m, m.startExercise(l)
+		}
+	}
+
+	_, cmd := m.lessonMapModel.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) updateQuotes(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, m.keys.Quotes.Close):
+			m.quotesModel.Close()
 			m.state = internal.StateMenu
 			return m, nil
+		case key.Matches(keyMsg, m.keys.Quotes.Start):
+			q, ok := m.quotesModel.Selected()
+			if !ok {
+				return m, nil
+			}
+			m.quotesModel.Close()
+			return m, m.startQuote(q)
+		case key.Matches(keyMsg, m.keys.Quotes.Favorite):
+			q, ok := m.quotesModel.Selected()
+			if !ok {
+				return m, nil
+			}
+			cmd := m.toggleFavorite(q.Key())
+			m.quotesModel.SetMarks(m.settings.Quote.Favorites, m.settings.Quote.Ratings)
+			return m, cmd
 		}
 	}
 
+	_, cmd := m.quotesModel.Update(msg)
 	return m, cmd
 }
 
+// updatePalette runs the chosen command after closing the palette, so a
+// command that switches screens lands on the new screen.
+func (m *Model) updatePalette(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(keyMsg, m.keys.Palette.Close):
+		m.showPalette = false
+		m.paletteModel.Close()
+		return m, nil
+	case key.Matches(keyMsg, m.keys.Palette.Run):
+		command, ok := m.paletteModel.Selected()
+		if !ok {
+			return m, nil
+		}
+		m.showPalette = false
+		m.paletteModel.Close()
+		return m, command.Run()
+	}
+
+	_, cmd := m.paletteModel.Update(keyMsg)
+	return m, cmd
+}
+
+func saveSettings(settings config.Config) tea.Cmd {
+	return func() tea.Msg {
+		if err := config.Save(settings); err != nil {
+			return shared.ErrMsg{Err: err}
+		}
+		return nil
+	}
+}
+
+// helpBinding and helpKeys pick the help key and bindings of the screen
+// that is currently shown.
+func (m *Model) helpBinding() key.Binding {
+	switch m.state {
+	case internal.StateTyping:
+		return m.keys.Typing.Help
+	case internal.StateResults, internal.StateFailed:
+		return m.keys.Results.Help
+	case internal.StateThemes:
+		return m.keys.Themes.Help
+	case internal.StateFunbox:
+		return m.keys.Funbox.Help
+	case internal.StateLessonMap:
+		return m.keys.LessonMap.Help
+	case internal.StateQuotes:
+		return m.keys.Quotes.Help
+	}
+	return m.keys.Menu.Help
+}
+
+func (m *Model) helpKeys() help.KeyMap {
+	switch m.state {
+	case internal.StateTyping:
+		return m.keys.Typing
+	case internal.StateResults, internal.StateFailed:
+		return m.keys.Results
+	case internal.StateThemes:
+		return m.keys.Themes
+	case internal.StateFunbox:
+		return m.keys.Funbox
+	case internal.StateQuotes:
+		return m.keys.Quotes
+	case internal.StateLessonMap:
+		return m.keys.LessonMap
+	}
+	return m.keys.Menu
+}
+
 func (m *Model) View() string {
+	return shared.PaintBackground(m.screen(), m.windowWidth, m.windowHeight) + m.clipboard
+}
+
+// screen is the view of whatever is showing, before the theme background
+// is painted behind it.
+func (m *Model) screen() string {
+	if m.showPalette {
+		return m.paletteModel.View()
+	}
+	if m.showHelp {
+		return shared.HelpOverlay("Keys", keymap.WithGlobal(m.helpKeys(), m.keys.Global), m.windowWidth, m.windowHeight)
+	}
+
 	switch m.state {
 	case internal.StateMenu:
 		return m.menuModel.View()
@@ -124,6 +991,16 @@ func (m *Model) View() string {
 		return m.typingModel.View()
 	case internal.StateResults:
 		return m.resultsModel.View()
+	case internal.StateFailed:
+		return m.failedModel.View()
+	case internal.StateThemes:
+		return m.themesModel.View()
+	case internal.StateFunbox:
+		return m.funboxModel.View()
+	case internal.StateQuotes:
+		return m.quotesModel.View()
+	case internal.StateLessonMap:
+		return m.lessonMapModel.View()
 	}
 	return ""
 }
@@ -0,0 +1,180 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"aiotype/internal/config"
+	"aiotype/internal/theme"
+)
+
+var ErrInvalidAccessibility = errors.New("invalid accessibility setting")
+
+type ColorblindMode int
+
+const (
+	ColorblindNone ColorblindMode = iota
+	// ColorblindRedGreen covers deuteranopia and protanopia, which the
+	// same colors serve.
+	ColorblindRedGreen
+	ColorblindTritanopia
+)
+
+type ErrorMarking int
+
+const (
+	ErrorMarkColor ErrorMarking = iota
+	ErrorMarkUnderline
+	ErrorMarkGlyph
+)
+
+type ExpectedCharPosition int
+
+const (
+	ExpectedCharOff ExpectedCharPosition = iota
+	ExpectedCharAbove
+	ExpectedCharBelow
+)
+
+// WrongSpaceGlyph stands in for a mistyped space in glyph marking, where
+// the mistake would otherwise be an invisible blank.
+const WrongSpaceGlyph = '·'
+
+type Accessibility struct {
+	Colorblind   ColorblindMode
+	HighContrast bool
+	ErrorMarking ErrorMarking
+	ExpectedChar ExpectedCharPosition
+}
+
+// colorblindColors are an error and error-extra color pair for dark and
+// for light backgrounds, since a color that stands out on one can vanish
+// on the other.
+type colorblindColors struct {
+	dark, light [2]string
+}
+
+// colorblindErrors holds error and error-extra colors that stay apart from
+// typical text colors for each kind of color vision deficiency. They are
+// drawn from the Okabe-Ito palette.
+var colorblindErrors = map[ColorblindMode]colorblindColors{
+	ColorblindRedGreen: {
+		dark:  [2]string{"#f0e442", "#0a3d62"},
+		light: [2]string{"#0072b2", "#56b4e9"},
+	},
+	ColorblindTritanopia: {
+		dark:  [2]string{"#e69f00", "#5a1e00"},
+		light: [2]string{"#d55e00", "#f0b27a"},
+	},
+}
+
+var accessibility Accessibility
+
+func ParseColorblindMode(value string) (ColorblindMode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "none", "off":
+		return ColorblindNone, nil
+	case "red-green", "deuteranopia", "deutan", "protanopia", "protan":
+		return ColorblindRedGreen, nil
+	case "tritanopia", "tritan":
+		return ColorblindTritanopia, nil
+	}
+	return ColorblindNone, fmt.Errorf("%w: colorblind %q", ErrInvalidAccessibility, value)
+}
+
+func ParseErrorMarking(value string) (ErrorMarking, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "color":
+		return ErrorMarkColor, nil
+	case "underline":
+		return ErrorMarkUnderline, nil
+	case "glyph":
+		return ErrorMarkGlyph, nil
+	}
+	return ErrorMarkColor, fmt.Errorf("%w: error marking %q", ErrInvalidAccessibility, value)
+}
+
+func ParseExpectedCharPosition(value string) (ExpectedCharPosition, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "off", "none":
+		return ExpectedCharOff, nil
+	case "above":
+		return ExpectedCharAbove, nil
+	case "below":
+		return ExpectedCharBelow, nil
+	}
+	return ExpectedCharOff, fmt.Errorf("%w: expected char %q", ErrInvalidAccessibility, value)
+}
+
+func AccessibilityFromConfig(cfg config.Accessibility) (Accessibility, error) {
+	colorblind, colorblindErr := ParseColorblindMode(cfg.Colorblind)
+	marking, markingErr := ParseErrorMarking(cfg.ErrorMarking)
+	expected, expectedErr := ParseExpectedCharPosition(cfg.ExpectedChar)
+
+	return Accessibility{
+		Colorblind:   colorblind,
+		HighContrast: cfg.HighContrast,
+		ErrorMarking: marking,
+		ExpectedChar: expected,
+	}, errors.Join(colorblindErr, markingErr, expectedErr)
+}
+
+func SetAccessibility(a Accessibility) {
+	accessibility = a
+	activeStyles = NewStyleSet(activeTheme)
+}
+
+func CurrentAccessibility() Accessibility {
+	return accessibility
+}
+
+// EffectiveTheme is the active theme after accessibility adjustments, for
+// code that blends theme colors itself instead of going through Styles.
+func EffectiveTheme() theme.Theme {
+	return applyAccessibility(activeTheme, accessibility)
+}
+
+func applyAccessibility(t theme.Theme, a Accessibility) theme.Theme {
+	if a.HighContrast {
+		if IsDarkColor(t.Bg) {
+			t.Bg = "#000000"
+			t.Text = "#ffffff"
+			t.Sub = "#b3b3b3"
+			t.Caret = "#ffffff"
+			t.Error = "#ff5555"
+			t.ErrorExtra = "#000000"
+		} else {
+			t.Bg = "#ffffff"
+			t.Text = "#000000"
+			t.Sub = "#595959"
+			t.Caret = "#000000"
+			t.Error = "#c00000"
+			t.ErrorExtra = "#ffffff"
+		}
+	}
+
+	if pairs, ok := colorblindErrors[a.Colorblind]; ok {
+		colors := pairs.light
+		if IsDarkColor(t.Bg) {
+			colors = pairs.dark
+		}
+		t.Error = colors[0]
+		t.ErrorExtra = colors[1]
+		t.ColorfulError = colors[0]
+		t.ColorfulErrorExtra = colors[1]
+		if a.HighContrast {
+			t.ErrorExtra = t.Bg
+		}
+	}
+
+	return t
+}
+
+// IsDarkColor reports whether a #rrggbb color has a relative luminance
+// below the midpoint, using the Rec. 709 luma weights.
+func IsDarkColor(hex string) bool {
+	r, g, b := HexToRGB(hex)
+	luminance := 0.2126*float64(r) + 0.7152*float64(g) + 0.0722*float64(b)
+	return luminance < 128
+}
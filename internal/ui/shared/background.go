@@ -0,0 +1,48 @@
+package shared
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// resetSeq ends every styled run lipgloss renders, which also drops the
+// background.
+const resetSeq = "\x1b[0m"
+
+// backgroundSeq is the escape sequence that sets the theme background,
+// and the text color so unstyled text reads on it, or empty when the
+// terminal cannot show it.
+func backgroundSeq(palette Palette) string {
+	if _, ok := palette.Bg.(lipgloss.NoColor); ok {
+		return ""
+	}
+	sample := lipgloss.NewStyle().Background(palette.Bg).Foreground(palette.Text).Render(" ")
+	seq, _, found := strings.Cut(sample, " ")
+	if !found {
+		return ""
+	}
+	return seq
+}
+
+// PaintBackground fills a whole screen with the theme background. Every
+// line is padded to width and the background is set again after each
+// styled run, since their resets would otherwise show the terminal's own
+// background. In 16 colors and without color the view is left alone.
+func PaintBackground(view string, width, height int) string {
+	seq := activeStyles.background
+	if seq == "" || width <= 0 {
+		return view
+	}
+
+	lines := strings.Split(view, "\n")
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	for i, line := range lines {
+		pad := max(width-lipgloss.Width(line), 0)
+		line = strings.ReplaceAll(line, resetSeq, resetSeq+seq)
+		lines[i] = seq + line + strings.Repeat(" ", pad) + resetSeq
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,226 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"aiotype/internal/theme"
+)
+
+var ErrInvalidColorMode = errors.New("invalid color mode")
+
+type ColorMode int
+
+const (
+	ColorModeNone ColorMode = iota
+	ColorMode16
+	ColorMode256
+	ColorModeTrueColor
+)
+
+const (
+	ColorSettingAuto      = "auto"
+	ColorSettingTrueColor = "truecolor"
+	ColorSetting256       = "256"
+	ColorSetting16        = "16"
+	ColorSettingNone      = "none"
+)
+
+const (
+	ansiBlack        = "0"
+	ansiRed          = "1"
+	ansiYellow       = "3"
+	ansiBlue         = "4"
+	ansiBrightBlack  = "8"
+	ansiBrightRed    = "9"
+	ansi256BrightRed = "196"
+)
+
+var (
+	colorMode      = ColorModeTrueColor
+	darkBackground = true
+)
+
+func (c ColorMode) String() string {
+	switch c {
+	case ColorModeNone:
+		return ColorSettingNone
+	case ColorMode16:
+		return ColorSetting16
+	case ColorMode256:
+		return ColorSetting256
+	default:
+		return ColorSettingTrueColor
+	}
+}
+
+// DetectColorMode resolves the color setting from the config file. "auto"
+// asks the terminal, which honours NO_COLOR and CLICOLOR/CLICOLOR_FORCE.
+func DetectColorMode(setting string) (ColorMode, error) {
+	switch strings.ToLower(strings.TrimSpace(setting)) {
+	case "", ColorSettingAuto:
+	case ColorSettingTrueColor:
+		return ColorModeTrueColor, nil
+	case ColorSetting256:
+		return ColorMode256, nil
+	case ColorSetting16:
+		return ColorMode16, nil
+	case ColorSettingNone:
+		return ColorModeNone, nil
+	default:
+		return ColorModeTrueColor, fmt.Errorf("%w: %q", ErrInvalidColorMode, setting)
+	}
+
+	if termenv.EnvNoColor() {
+		return ColorModeNone, nil
+	}
+
+	switch termenv.NewOutput(os.Stdout).EnvColorProfile() {
+	case termenv.TrueColor:
+		return ColorModeTrueColor, nil
+	case termenv.ANSI256:
+		return ColorMode256, nil
+	case termenv.ANSI:
+		return ColorMode16, nil
+	default:
+		return ColorModeNone, nil
+	}
+}
+
+func DetectDarkBackground() bool {
+	return termenv.HasDarkBackground()
+}
+
+// SetColorMode installs the terminal capabilities that every StyleSet is
+// mapped onto. No-color mode still emits text attributes, since NO_COLOR
+// only forbids color, so the renderer is kept at the basic ANSI profile.
+func SetColorMode(mode ColorMode, dark bool) {
+	colorMode = mode
+	darkBackground = dark
+
+	switch mode {
+	case ColorModeTrueColor:
+		lipgloss.SetColorProfile(termenv.TrueColor)
+	case ColorMode256:
+		lipgloss.SetColorProfile(termenv.ANSI256)
+	default:
+		lipgloss.SetColorProfile(termenv.ANSI)
+	}
+	lipgloss.SetHasDarkBackground(dark)
+
+	activeStyles = NewStyleSet(activeTheme)
+}
+
+func CurrentColorMode() ColorMode {
+	return colorMode
+}
+
+func HasDarkBackground() bool {
+	return darkBackground
+}
+
+type Palette struct {
+	Bg         lipgloss.TerminalColor
+	Main       lipgloss.TerminalColor
+	Caret      lipgloss.TerminalColor
+	Sub        lipgloss.TerminalColor
+	SubAlt     lipgloss.TerminalColor
+	Text       lipgloss.TerminalColor
+	Error      lipgloss.TerminalColor
+	ErrorExtra lipgloss.TerminalColor
+}
+
+// NewPalette maps a theme onto the colors the terminal can show. Below
+// truecolor, nearby theme colors can collapse onto the same palette entry,
+// so errors are forced to a red that stays distinct from the text color.
+func NewPalette(t theme.Theme, mode ColorMode) Palette {
+	switch mode {
+	case ColorModeNone:
+		return Palette{
+			Bg:         lipgloss.NoColor{},
+			Main:       lipgloss.NoColor{},
+			Caret:      lipgloss.NoColor{},
+			Sub:        lipgloss.NoColor{},
+			SubAlt:     lipgloss.NoColor{},
+			Text:       lipgloss.NoColor{},
+			Error:      lipgloss.NoColor{},
+			ErrorExtra: lipgloss.NoColor{},
+		}
+
+	case ColorMode16:
+		return newANSIPalette(t)
+
+	case ColorMode256:
+		p := Palette{
+			Bg:         convert(termenv.ANSI256, t.Bg),
+			Main:       convert(termenv.ANSI256, t.Main),
+			Caret:      convert(termenv.ANSI256, t.Caret),
+			Sub:        convert(termenv.ANSI256, t.Sub),
+			SubAlt:     convert(termenv.ANSI256, t.SubAlt),
+			Text:       convert(termenv.ANSI256, t.Text),
+			Error:      convert(termenv.ANSI256, t.Error),
+			ErrorExtra: convert(termenv.ANSI256, t.ErrorExtra),
+		}
+		if p.Error == p.Text || p.Error == p.Sub {
+			p.Error = lipgloss.Color(ansi256BrightRed)
+		}
+		return p
+	}
+
+	return Palette{
+		Bg:         lipgloss.Color(t.Bg),
+		Main:       lipgloss.Color(t.Main),
+		Caret:      lipgloss.Color(t.Caret),
+		Sub:        lipgloss.Color(t.Sub),
+		SubAlt:     lipgloss.Color(t.SubAlt),
+		Text:       lipgloss.Color(t.Text),
+		Error:      lipgloss.Color(t.Error),
+		ErrorExtra: lipgloss.Color(t.ErrorExtra),
+	}
+}
+
+// newANSIPalette never paints the theme background in 16 colors, so text
+// uses the terminal's default foreground to stay readable on both light
+// and dark terminals.
+func newANSIPalette(t theme.Theme) Palette {
+	main := convert(termenv.ANSI, t.Main)
+	switch main {
+	case lipgloss.Color(ansiBlack), lipgloss.Color("7"), lipgloss.Color(ansiBrightBlack), lipgloss.Color("15"):
+		main = lipgloss.Color(ansiYellow)
+		if !darkBackground {
+			main = lipgloss.Color(ansiBlue)
+		}
+	}
+
+	errorColor := lipgloss.Color(ansiBrightRed)
+	if !darkBackground {
+		errorColor = lipgloss.Color(ansiRed)
+	}
+
+	return Palette{
+		Bg:         lipgloss.NoColor{},
+		Main:       main,
+		Caret:      lipgloss.NoColor{},
+		Sub:        lipgloss.Color(ansiBrightBlack),
+		SubAlt:     lipgloss.NoColor{},
+		Text:       lipgloss.NoColor{},
+		Error:      errorColor,
+		ErrorExtra: lipgloss.NoColor{},
+	}
+}
+
+func convert(profile termenv.Profile, hex string) lipgloss.TerminalColor {
+	switch c := profile.Convert(termenv.RGBColor(hex)).(type) {
+	case termenv.ANSI256Color:
+		return lipgloss.Color(fmt.Sprintf("%d", int(c)))
+	case termenv.ANSIColor:
+		return lipgloss.Color(fmt.Sprintf("%d", int(c)))
+	default:
+		return lipgloss.Color(hex)
+	}
+}
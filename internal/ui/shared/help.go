@@ -0,0 +1,45 @@
+package shared
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func newHelpModel() help.Model {
+	styles := Styles()
+	h := help.New()
+	h.ShortSeparator = " • "
+	h.Styles.ShortKey = styles.SubText
+	h.Styles.ShortDesc = styles.SubText
+	h.Styles.ShortSeparator = styles.SubText
+	h.Styles.FullKey = styles.StatValue
+	h.Styles.FullDesc = styles.StatLabel
+	h.Styles.FullSeparator = styles.SubText
+	h.Styles.Ellipsis = styles.SubText
+	return h
+}
+
+// HelpLine renders the one-line key hints shown under each screen.
+func HelpLine(keys help.KeyMap) string {
+	return Styles().Help.Render(newHelpModel().ShortHelpView(keys.ShortHelp()))
+}
+
+// HelpOverlay renders every binding of a screen in a centered box, for the
+// "?" help overlay.
+func HelpOverlay(title string, keys help.KeyMap, width, height int) string {
+	styles := Styles()
+	content := lipgloss.JoinVertical(
+		lipgloss.Center,
+		styles.ResultTitle.Render(title),
+		newHelpModel().FullHelpView(keys.FullHelp()),
+		styles.Help.Render("press any key to close"),
+	)
+
+	return lipgloss.Place(
+		width,
+		height,
+		lipgloss.Center,
+		lipgloss.Center,
+		styles.ResultsContainer.UnsetMarginTop().Render(content),
+	)
+}
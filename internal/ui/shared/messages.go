@@ -6,10 +6,23 @@ import (
 	"github.com/charmbracelet/bubbletea"
 )
 
-type TickMsg time.Time
+// TickMsg drives a running test. Chain tells tick loops apart, so a loop
+// left over from an earlier test can be dropped.
+type TickMsg struct {
+	Time  time.Time
+	Chain int
+}
 
-func TickEvery() tea.Cmd {
+func TickEvery(chain int) tea.Cmd {
 	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
-		return TickMsg(t)
+		return TickMsg{Time: t, Chain: chain}
 	})
 }
+
+type ErrMsg struct {
+	Err error
+}
+
+func (e ErrMsg) Error() string {
+	return e.Err.Error()
+}
@@ -5,85 +5,182 @@ import (
 	"strconv"
 
 	"github.com/charmbracelet/lipgloss"
-)
 
-const (
-	colorGray    = "#646669"
-	colorWhite   = "#ffffff"
-	colorRed     = "#ff0000"
-	colorPrimary = "#e2b714"
-	colorBlack   = "#000000"
-	colorDarkRed = "#4d0000"
+	"aiotype/internal/theme"
 )
 
+type StyleSet struct {
+	Base             lipgloss.Style
+	Title            lipgloss.Style
+	Subtitle         lipgloss.Style
+	SubText          lipgloss.Style
+	Text             lipgloss.Style
+	ErrorText        lipgloss.Style
+	TextInErrorWord  lipgloss.Style
+	ErrorInErrorWord lipgloss.Style
+	Cursor           lipgloss.Style
+	StatLabel        lipgloss.Style
+	StatValue        lipgloss.Style
+	ResultsContainer lipgloss.Style
+	ResultTitle      lipgloss.Style
+	Help             lipgloss.Style
+	Selected         lipgloss.Style
+	ProgressError    lipgloss.Style
+	ExpectedHint     lipgloss.Style
+	CaretAccent      lipgloss.Style
+	CaretOutline     lipgloss.Style
+	PaceCaret        lipgloss.Style
+
+	// background sets the theme background, for PaintBackground.
+	background string
+}
+
 var (
-	grayColor    = lipgloss.Color(colorGray)
-	whiteColor   = lipgloss.Color(colorWhite)
-	redColor     = lipgloss.Color(colorRed)
-	primaryColor = lipgloss.Color(colorPrimary)
-	darkRedColor = lipgloss.Color(colorDarkRed)
+	activeTheme  = theme.Default()
+	activeStyles = NewStyleSet(activeTheme)
 )
 
-var (
-	BaseStyle = lipgloss.NewStyle().
+func NewStyleSet(t theme.Theme) *StyleSet {
+	palette := NewPalette(applyAccessibility(t, accessibility), colorMode)
+
+	styles := &StyleSet{
+		background: backgroundSeq(palette),
+
+		Base: lipgloss.NewStyle().
 			Padding(1, 0).
-			Foreground(whiteColor)
+			Foreground(palette.Text),
 
-	TitleStyle = lipgloss.NewStyle().
-			Foreground(primaryColor).
+		Title: lipgloss.NewStyle().
+			Foreground(palette.Main).
 			Bold(true).
 			Align(lipgloss.Center).
-			MarginBottom(1)
+			MarginBottom(1),
 
-	SubtitleStyle = lipgloss.NewStyle().
-			Foreground(grayColor).
+		Subtitle: lipgloss.NewStyle().
+			Foreground(palette.Sub).
 			Align(lipgloss.Center).
-			MarginBottom(2)
+			MarginBottom(2),
 
-	GrayTextStyle = lipgloss.NewStyle().
-			Foreground(grayColor)
+		SubText: lipgloss.NewStyle().
+			Foreground(palette.Sub),
 
-	WhiteTextStyle = lipgloss.NewStyle().
-			Foreground(whiteColor)
+		Text: lipgloss.NewStyle().
+			Foreground(palette.Text),
 
-	RedTextStyle = lipgloss.NewStyle().
-			Foreground(redColor)
+		ErrorText: lipgloss.NewStyle().
+			Foreground(palette.Error),
 
-	WhiteTextRedBgStyle = lipgloss.NewStyle().
-				Foreground(whiteColor).
-				Background(darkRedColor)
+		TextInErrorWord: lipgloss.NewStyle().
+			Foreground(palette.Text).
+			Background(palette.ErrorExtra),
 
-	RedTextRedBgStyle = lipgloss.NewStyle().
-				Foreground(redColor).
-				Background(darkRedColor)
+		ErrorInErrorWord: lipgloss.NewStyle().
+			Foreground(palette.Error).
+			Background(palette.ErrorExtra),
 
-	CursorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color(colorBlack)).
-			Background(whiteColor)
+		Cursor: lipgloss.NewStyle().
+			Foreground(palette.Bg).
+			Background(palette.Caret),
 
-	StatLabelStyle = lipgloss.NewStyle().
-			Foreground(grayColor)
+		StatLabel: lipgloss.NewStyle().
+			Foreground(palette.Sub),
 
-	StatValueStyle = lipgloss.NewStyle().
-			Foreground(primaryColor)
+		StatValue: lipgloss.NewStyle().
+			Foreground(palette.Main),
 
-	ResultsContainerStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(primaryColor).
-				Padding(1, 2).
-				MarginTop(2)
+		ResultsContainer: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(palette.Main).
+			Padding(1, 2).
+			MarginTop(2),
 
-	ResultTitleStyle = lipgloss.NewStyle().
-				Foreground(primaryColor).
-				Bold(true).
-				Align(lipgloss.Center).
-				MarginBottom(1)
+		ResultTitle: lipgloss.NewStyle().
+			Foreground(palette.Main).
+			Bold(true).
+			Align(lipgloss.Center).
+			MarginBottom(1),
 
-	HelpStyle = lipgloss.NewStyle().
-			Foreground(grayColor).
+		Help: lipgloss.NewStyle().
+			Foreground(palette.Sub).
 			Align(lipgloss.Center).
-			MarginTop(1)
-)
+			MarginTop(1),
+
+		Selected: lipgloss.NewStyle().
+			Foreground(palette.Main).
+			Bold(true),
+
+		CaretAccent: lipgloss.NewStyle().
+			Foreground(palette.Caret),
+
+		CaretOutline: lipgloss.NewStyle().
+			Foreground(palette.Caret).
+			Background(palette.SubAlt),
+
+		PaceCaret: lipgloss.NewStyle().
+			Background(palette.SubAlt),
+	}
+
+	switch colorMode {
+	case ColorMode16:
+		// There is no dark red to put behind a word with an error, and the
+		// caret has no theme color to sit on, so use attributes instead.
+		styles.TextInErrorWord = lipgloss.NewStyle().Foreground(palette.Text).Underline(true)
+		styles.ErrorInErrorWord = lipgloss.NewStyle().Foreground(palette.Error).Underline(true)
+		styles.Cursor = lipgloss.NewStyle().Reverse(true)
+		styles.CaretAccent = styles.CaretAccent.Bold(true)
+		styles.CaretOutline = lipgloss.NewStyle().Bold(true).Underline(true)
+		styles.PaceCaret = lipgloss.NewStyle().Underline(true)
+
+	case ColorModeNone:
+		styles.SubText = styles.SubText.Faint(true)
+		styles.Subtitle = styles.Subtitle.Faint(true)
+		styles.StatLabel = styles.StatLabel.Faint(true)
+		styles.Help = styles.Help.Faint(true)
+		styles.StatValue = styles.StatValue.Bold(true)
+		styles.ErrorText = styles.ErrorText.Underline(true).Bold(true)
+		styles.TextInErrorWord = lipgloss.NewStyle().Underline(true)
+		styles.ErrorInErrorWord = lipgloss.NewStyle().Underline(true).Bold(true)
+		styles.Cursor = lipgloss.NewStyle().Reverse(true)
+		styles.CaretAccent = styles.CaretAccent.Bold(true)
+		styles.CaretOutline = lipgloss.NewStyle().Bold(true).Underline(true)
+		styles.PaceCaret = lipgloss.NewStyle().Underline(true)
+	}
+
+	if accessibility.HighContrast && colorMode != ColorModeNone {
+		styles.ErrorText = styles.ErrorText.Bold(true)
+		styles.TextInErrorWord = lipgloss.NewStyle().Foreground(palette.Text).Underline(true)
+		styles.ErrorInErrorWord = lipgloss.NewStyle().Foreground(palette.Bg).Background(palette.Error).Bold(true)
+	}
+
+	// The border only shows progress, so it keeps the plain error color
+	// while typed text picks up the non-color error markers.
+	styles.ProgressError = styles.ErrorText
+	styles.ExpectedHint = styles.SubText
+
+	switch accessibility.ErrorMarking {
+	case ErrorMarkUnderline:
+		styles.ErrorText = styles.ErrorText.Underline(true)
+		styles.ErrorInErrorWord = styles.ErrorInErrorWord.Underline(true)
+	case ErrorMarkGlyph:
+		styles.ErrorText = styles.ErrorText.Strikethrough(true)
+		styles.ErrorInErrorWord = styles.ErrorInErrorWord.Strikethrough(true)
+	}
+
+	return styles
+}
+
+func SetTheme(t theme.Theme) {
+	activeTheme = t
+	activeStyles = NewStyleSet(t)
+}
+
+func Theme() theme.Theme {
+	return activeTheme
+}
+
+func Styles() *StyleSet {
+	return activeStyles
+}
 
 func HexToRGB(hex string) (int, int, int) {
 	hex = hex[1:]
@@ -0,0 +1,84 @@
+package themes
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbletea"
+
+	"aiotype/internal/theme"
+	"aiotype/internal/ui/keymap"
+	"aiotype/internal/ui/shared"
+)
+
+type Model struct {
+	keys         keymap.ThemesKeyMap
+	registry     *theme.Registry
+	themes       []theme.Theme
+	cursor       int
+	original     theme.Theme
+	windowWidth  int
+	windowHeight int
+}
+
+func NewModel(keys keymap.ThemesKeyMap, registry *theme.Registry) *Model {
+	return &Model{
+		keys:     keys,
+		registry: registry,
+		themes:   registry.All(),
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.windowWidth = msg.Width
+		m.windowHeight = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			m.move(-1)
+		case key.Matches(msg, m.keys.Down):
+			m.move(1)
+		case key.Matches(msg, m.keys.Top):
+			m.move(-m.cursor)
+		case key.Matches(msg, m.keys.Bottom):
+			m.move(len(m.themes) - 1 - m.cursor)
+		}
+	}
+	return m, nil
+}
+
+// Open snapshots the active theme so Cancel can restore it after the user
+// has been previewing other themes.
+func (m *Model) Open() {
+	m.themes = m.registry.All()
+	m.original = shared.Theme()
+	m.cursor = m.registry.Index(m.original.Name)
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+func (m *Model) Cancel() {
+	shared.SetTheme(m.original)
+}
+
+func (m *Model) Selected() theme.Theme {
+	if len(m.themes) == 0 {
+		return m.original
+	}
+	return m.themes[m.cursor]
+}
+
+func (m *Model) move(delta int) {
+	if len(m.themes) == 0 {
+		return
+	}
+	m.cursor = (m.cursor + delta + len(m.themes)) % len(m.themes)
+	shared.SetTheme(m.themes[m.cursor])
+}
@@ -0,0 +1,129 @@
+package themes
+
+import (
+	"strings"
+
+	"aiotype/internal/theme"
+	"aiotype/internal/ui/shared"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	visibleThemes = 12
+	listWidth     = 28
+	previewWidth  = 44
+)
+
+func (m *Model) View() string {
+	styles := shared.Styles()
+	title := styles.Title.Render("Themes")
+
+	body := lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		m.renderList(),
+		"  ",
+		renderPreview(m.Selected()),
+	)
+
+	help := shared.HelpLine(m.keys)
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Center,
+		title,
+		body,
+		help,
+	)
+
+	return lipgloss.Place(
+		m.windowWidth,
+		m.windowHeight,
+		lipgloss.Center,
+		lipgloss.Center,
+		content,
+	)
+}
+
+func (m *Model) renderList() string {
+	styles := shared.Styles()
+
+	start := m.cursor - visibleThemes/2
+	if start > len(m.themes)-visibleThemes {
+		start = len(m.themes) - visibleThemes
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + visibleThemes
+	if end > len(m.themes) {
+		end = len(m.themes)
+	}
+
+	lines := make([]string, 0, visibleThemes)
+	for i := start; i < end; i++ {
+		t := m.themes[i]
+		name := t.Name
+		if i == m.cursor {
+			name = styles.Selected.Render("> " + name)
+		} else {
+			name = styles.SubText.Render("  " + name)
+		}
+		lines = append(lines, swatch(t)+" "+name)
+	}
+
+	return lipgloss.NewStyle().Width(listWidth).Render(strings.Join(lines, "\n"))
+}
+
+func swatch(t theme.Theme) string {
+	if shared.CurrentColorMode() == shared.ColorModeNone {
+		return ""
+	}
+
+	palette := shared.NewPalette(t, shared.CurrentColorMode())
+	var b strings.Builder
+	for _, color := range []lipgloss.TerminalColor{palette.Bg, palette.Main, palette.Text, palette.Error} {
+		b.WriteString(lipgloss.NewStyle().Background(color).Render(" "))
+	}
+	return b.String()
+}
+
+// renderPreview draws a sample of the typing screen in t. Every segment
+// carries the theme background because the terminal's own background
+// shows through wherever an inner style resets it.
+func renderPreview(t theme.Theme) string {
+	palette := shared.NewPalette(t, shared.CurrentColorMode())
+	bg := palette.Bg
+	styles := shared.NewStyleSet(t)
+	on := func(style lipgloss.Style, text string) string {
+		if _, ok := style.GetBackground().(lipgloss.NoColor); ok {
+			style = style.Background(bg)
+		}
+		return style.Render(text)
+	}
+
+	lines := []string{
+		on(styles.StatValue, "30s") + on(styles.SubText, " | ") + on(styles.StatValue, "87 wpm"),
+		"",
+		on(styles.Text, "the quick ") +
+			on(styles.TextInErrorWord, "b") + on(styles.ErrorInErrorWord, "t") + on(styles.TextInErrorWord, "own") +
+			on(styles.Text, " fox j") +
+			on(styles.Cursor, "u") +
+			on(styles.SubText, "mps over"),
+		on(styles.SubText, "the lazy dog and keeps on"),
+		on(styles.SubText, "typing until the timer ends"),
+		"",
+		on(styles.Title.UnsetMarginBottom(), t.Name),
+	}
+
+	line := lipgloss.NewStyle().Background(bg).Width(previewWidth - 4)
+	for i, l := range lines {
+		lines[i] = line.Render(l)
+	}
+
+	return lipgloss.NewStyle().
+		Background(bg).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Sub).
+		BorderBackground(bg).
+		Render(strings.Join(lines, "\n"))
+}
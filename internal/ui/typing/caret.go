@@ -0,0 +1,159 @@
+package typing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"aiotype/internal"
+	"aiotype/internal/ui/shared"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var ErrInvalidCaret = errors.New("invalid caret setting")
+
+type CaretStyle int
+
+const (
+	CaretBlock CaretStyle = iota
+	CaretLine
+	CaretUnderline
+	CaretOutline
+	CaretOff
+)
+
+type PaceMode int
+
+const (
+	PaceOff PaceMode = iota
+	PaceWPM
+	PacePB
+	PaceAverage
+)
+
+const (
+	CaretLineGlyph    = "▏"
+	CaretBlinkPeriod  = 530 * time.Millisecond
+	CaretBlinkIdle    = time.Second
+	caretFadeMidpoint = 0.5
+)
+
+func ParseCaretStyle(value string) (CaretStyle, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "block":
+		return CaretBlock, nil
+	case "line":
+		return CaretLine, nil
+	case "underline":
+		return CaretUnderline, nil
+	case "outline":
+		return CaretOutline, nil
+	case "off", "none":
+		return CaretOff, nil
+	}
+	return CaretBlock, fmt.Errorf("%w: style %q", ErrInvalidCaret, value)
+}
+
+func ParsePaceMode(value string) (PaceMode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "off":
+		return PaceOff, nil
+	case "wpm":
+		return PaceWPM, nil
+	case "pb":
+		return PacePB, nil
+	case "average", "avg":
+		return PaceAverage, nil
+	}
+	return PaceOff, fmt.Errorf("%w: pace %q", ErrInvalidCaret, value)
+}
+
+func (m *Model) SetCaret(style CaretStyle, blink bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.caretStyle = style
+	m.caretBlink = blink
+}
+
+// SetPaceWPM sets the speed of the pace caret. Zero or less hides it.
+func (m *Model) SetPaceWPM(wpm float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.paceWPM = wpm
+}
+
+// caretColumns is how many extra cells the caret takes up. The line caret
+// is drawn as its own glyph in front of the next character.
+func (m *Model) caretColumns() int {
+	if m.caretStyle == CaretLine {
+		return 1
+	}
+	return 0
+}
+
+// caretVisible blinks the caret only while the typist is idle, so it never
+// disappears in the middle of a burst.
+func (m *Model) caretVisible() bool {
+	if m.caretStyle == CaretOff {
+		return false
+	}
+	if !m.caretBlink || time.Since(m.lastKeystroke) < CaretBlinkIdle {
+		return true
+	}
+	return time.Now().UnixNano()/int64(CaretBlinkPeriod)%2 == 0
+}
+
+func (m *Model) renderCaret(char rune, result *strings.Builder) {
+	styles := shared.Styles()
+
+	if !m.caretVisible() {
+		if m.caretStyle == CaretLine {
+			result.WriteString(" ")
+		}
+		result.WriteString(styles.SubText.Render(string(char)))
+		return
+	}
+
+	switch m.caretStyle {
+	case CaretLine:
+		result.WriteString(m.getCaretAccentStyle().Render(CaretLineGlyph))
+		result.WriteString(styles.SubText.Render(string(char)))
+	case CaretUnderline:
+		result.WriteString(m.getCaretAccentStyle().Underline(true).Render(string(char)))
+	case CaretOutline:
+		result.WriteString(m.getCaretAccentStyle().Inherit(styles.CaretOutline).Render(string(char)))
+	default:
+		result.WriteString(m.getCurrentCursorStyle().Render(string(char)))
+	}
+}
+
+// getCaretAccentStyle is the caret color for carets that draw in the
+// foreground, pulsing towards the error color in the last seconds.
+func (m *Model) getCaretAccentStyle() lipgloss.Style {
+	if !m.isInLastFiveSeconds() {
+		return shared.Styles().CaretAccent
+	}
+	fadeFactor := m.getFadeFactor()
+	if shared.CurrentColorMode() < shared.ColorMode256 {
+		if fadeFactor > caretFadeMidpoint {
+			return shared.Styles().ErrorText.Bold(true)
+		}
+		return shared.Styles().CaretAccent
+	}
+	activeTheme := shared.EffectiveTheme()
+	interpolatedColor := shared.InterpolateColor(activeTheme.Caret, activeTheme.Error, fadeFactor)
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(interpolatedColor))
+}
+
+// paceCaretIndex is where a typist going exactly at the pace WPM would be
+// now, or -1 when there is no pace caret to draw.
+func (m *Model) paceCaretIndex() int {
+	if m.paceWPM <= 0 || m.currentTest == nil || m.currentTest.StartTime.IsZero() {
+		return -1
+	}
+
+	elapsed := internal.ActiveDuration(m.currentTest, time.Now())
+	charsPerSecond := m.paceWPM * internal.CharsPerWord / 60
+	return int(charsPerSecond * elapsed.Seconds())
+}
@@ -20,20 +20,24 @@ type ProgressBorder struct {
 
 	TimeLeft string
 	WpmText  string
+	// RequiredText is the minimum pace, shown after the WPM. Warning
+	// draws the stats in the error color while the pace is below it.
+	RequiredText string
+	Warning      bool
 }
 
 func (pb *ProgressBorder) GetBorderStyleForPosition(pos int) lipgloss.Style {
 	if pos < 0 || pos >= pb.Perimeter {
-		return shared.GrayTextStyle
+		return shared.Styles().SubText
 	}
 
 	if pos < pb.CorrectEnd {
-		return shared.WhiteTextStyle
+		return shared.Styles().Text
 	}
 	if pos < pb.ErrorEnd {
-		return shared.RedTextStyle
+		return shared.Styles().ProgressError
 	}
-	return shared.GrayTextStyle
+	return shared.Styles().SubText
 }
 
 func (pb *ProgressBorder) Render() string {
@@ -74,9 +78,15 @@ func (pb *ProgressBorder) renderTopBorderWithStats() string {
 
 func (pb *ProgressBorder) formatStatsText() string {
 	statsText := fmt.Sprintf("Time: %s | WPM: %s", pb.TimeLeft, pb.WpmText)
+	if pb.RequiredText != "" {
+		statsText += " | " + pb.RequiredText
+	}
 	availableSpace := pb.Width - 6
 
 	if len(statsText) > availableSpace {
+		if pb.RequiredText != "" {
+			return fmt.Sprintf("%s|%s|%s", pb.TimeLeft, pb.WpmText, pb.RequiredText)
+		}
 		return fmt.Sprintf("%s|%s", pb.TimeLeft, pb.WpmText)
 	}
 	return statsText
@@ -95,7 +105,11 @@ func (pb *ProgressBorder) buildTopBorderWithStats(statsText string) string {
 	result.WriteString(pb.GetBorderStyleForPosition(0).Render("╭"))
 	result.WriteString(pb.GetBorderStyleForPosition(1).Render("─"))
 	result.WriteString(" ")
-	result.WriteString(statsText)
+	if pb.Warning {
+		result.WriteString(shared.Styles().ProgressError.Render(statsText))
+	} else {
+		result.WriteString(statsText)
+	}
 	result.WriteString(" ")
 
 	for i := 0; i < remainingDashes; i++ {
@@ -20,4 +20,9 @@ const (
 	ExtraLinesPadding     = 4
 	PerimeterBorderAdjust = 4
 	MinSafetyPadding      = 2
+	DefaultVisibleLines   = 3
+	MinTapeTerminalWidth  = 20
+	MinTapeTerminalHeight = 5
+	CompactTerminalHeight = 9
+	DefaultTapeCaretPct   = 40
 )
@@ -0,0 +1,71 @@
+package typing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"aiotype/internal"
+	"github.com/charmbracelet/bubbletea"
+)
+
+var ErrInvalidPasteMode = errors.New("invalid paste mode")
+
+type PasteMode int
+
+const (
+	PasteReject PasteMode = iota
+	PasteRecord
+)
+
+const pasteRejectedNotice = "Paste is off • type the text yourself"
+
+func ParsePasteMode(value string) (PasteMode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "reject":
+		return PasteReject, nil
+	case "record":
+		return PasteRecord, nil
+	}
+	return PasteReject, fmt.Errorf("%w: %q", ErrInvalidPasteMode, value)
+}
+
+func (m *Model) SetPasteMode(mode PasteMode) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pasteMode = mode
+}
+
+// typeKey feeds a key message into the test. Terminals batch fast typing
+// and IME commits into one message with several runes, so every rune is
+// typed. Must be called with m.mu held.
+func (m *Model) typeKey(msg tea.KeyMsg) {
+	if m.currentTest == nil || msg.Alt {
+		return
+	}
+
+	if msg.Paste {
+		if m.pasteMode == PasteRecord {
+			internal.ProcessPaste(m.currentTest, string(msg.Runes))
+		} else {
+			m.notice = pasteRejectedNotice
+		}
+		return
+	}
+
+	switch msg.Type {
+	case tea.KeyRunes, tea.KeySpace:
+	default:
+		return
+	}
+
+	for _, char := range msg.Runes {
+		typeChar := internal.ProcessCharacter
+		if internal.IsCombiningMark(char) {
+			typeChar = internal.ComposeCharacter
+		}
+		if typeChar(m.currentTest, char) {
+			return
+		}
+	}
+}
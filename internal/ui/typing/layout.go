@@ -1,7 +1,9 @@
 package typing
 
 import (
+	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"aiotype/internal"
 	"aiotype/internal/ui/typing/components"
@@ -97,18 +99,57 @@ func (m *Model) renderTypingBox(processedContent []string, timeLeft string, wpmT
 		ErrorEnd:     errorEnd,
 		TimeLeft:     timeLeft,
 		WpmText:      wpmText,
+		RequiredText: m.requiredPaceText(),
+		Warning:      m.belowRequiredPace(),
 	}
 
 	return pb.Render()
 }
 
+// requiredPaceText lists the minimum thresholds of the test, e.g.
+// "min 60 wpm 95% 80 burst".
+func (m *Model) requiredPaceText() string {
+	limits := m.currentTest.Minimums
+	if !limits.Enabled() {
+		return ""
+	}
+
+	parts := []string{"min"}
+	if limits.WPM > 0 {
+		parts = append(parts, fmt.Sprintf("%.0f wpm", limits.WPM))
+	}
+	if limits.Accuracy > 0 {
+		parts = append(parts, fmt.Sprintf("%.0f%%", limits.Accuracy))
+	}
+	if limits.Burst > 0 {
+		parts = append(parts, fmt.Sprintf("%.0f burst", limits.Burst))
+	}
+	return strings.Join(parts, " ")
+}
+
+// belowRequiredPace warns as soon as the pace drops under a threshold,
+// including during the grace period, before it can fail the test.
+func (m *Model) belowRequiredPace() bool {
+	test := m.currentTest
+	limits := test.Minimums
+	if test.StartTime.IsZero() {
+		return false
+	}
+	return (limits.WPM > 0 && m.realTimeWPM < limits.WPM) ||
+		(limits.Accuracy > 0 && internal.CalculateAccuracy(test) < limits.Accuracy) ||
+		(limits.Burst > 0 && test.LastBurst > 0 && test.LastBurst < limits.Burst)
+}
+
 func (m *Model) calculateProgressBounds(perimeter int) (correctEnd, errorEnd int) {
 	if m.currentTest == nil {
 		return 0, 0
 	}
 
 	correctChars := internal.CountCorrectChars(m.currentTest)
-	totalChars := len(m.currentTest.TargetText)
+	if m.blind {
+		correctChars = m.currentTest.CurrentPos
+	}
+	totalChars := utf8.RuneCountInString(m.currentTest.TargetText)
 
 	if totalChars == 0 {
 		return 0, 0
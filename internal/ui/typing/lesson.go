@@ -0,0 +1,53 @@
+package typing
+
+import (
+	"fmt"
+	"strings"
+
+	"aiotype/internal"
+	"aiotype/internal/lesson"
+	"aiotype/internal/ui/shared"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// renderLessonHeader draws the alphabet in unlock order, with learned,
+// unlocked, focus and locked letters told apart, over a line on the focus
+// letter. Only lesson tests show it.
+func (m *Model) renderLessonHeader() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.lesson == nil || m.currentTest == nil || m.currentTest.Mode != internal.ModeLesson {
+		return ""
+	}
+
+	styles := shared.Styles()
+	focus := m.lesson.Focus(m.lessonTargets)
+	unlocked := len(m.lesson.Letters())
+	letters := make([]string, 0, len(lesson.Alphabet))
+	for i, letter := range lesson.Alphabet {
+		style := styles.Text
+		switch {
+		case i >= unlocked:
+			style = styles.SubText
+		case letter == focus:
+			style = styles.Selected.Underline(true)
+		case m.lesson.Key(letter).Learned(m.lessonTargets):
+			style = styles.StatValue
+		}
+		letters = append(letters, style.Render(string(letter)))
+	}
+
+	status := fmt.Sprintf("%d/%d letters • every letter learned", unlocked, len(lesson.Alphabet))
+	if focus != 0 {
+		key := m.lesson.Key(focus)
+		status = fmt.Sprintf("%d/%d letters • focus %c", unlocked, len(lesson.Alphabet), focus)
+		if key.Time > 0 {
+			status += fmt.Sprintf(" • %.0f/%.0f wpm • %.0f/%.0f%%",
+				key.WPM(), m.lessonTargets.WPM, key.Accuracy, m.lessonTargets.Accuracy)
+		}
+	}
+	return lipgloss.JoinVertical(lipgloss.Center,
+		strings.Join(letters, " "),
+		styles.SubText.Render(status),
+	)
+}
@@ -2,38 +2,105 @@ package typing
 
 import (
 	"math"
+	"math/rand"
 	"sync"
 	"time"
 
 	"aiotype/internal"
+	"aiotype/internal/funbox"
+	"aiotype/internal/lesson"
+	"aiotype/internal/quote"
+	"aiotype/internal/ui/keymap"
 	"aiotype/internal/ui/shared"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Generator makes the words of a test, e.g. from a Markov chain or from
+// the typist's weaknesses.
+type Generator interface {
+	Words(n int) []string
+}
+
 type Model struct {
+	keys          keymap.TypingKeyMap
 	currentTest   *internal.TypingTest
 	config        internal.GameConfig
 	windowWidth   int
 	windowHeight  int
 	realTimeWPM   float64
 	fadeStartTime time.Time
-	mu            sync.RWMutex
+	visibleLines  int
+	layout        Layout
+	tapeScroll    TapeScroll
+	// tapeCaretPercent is the caret column in tape layout, as a percentage
+	// of the tape width.
+	tapeCaretPercent int
+	caretStyle       CaretStyle
+	caretBlink       bool
+	paceWPM          float64
+	lastKeystroke    time.Time
+	prompt           string
+	validity         internal.ValidityRules
+	pasteMode        PasteMode
+	// notice explains why the last key was ignored, until the next key.
+	notice   string
+	funboxes funbox.Set
+	// generator makes the words of new tests; nil uses random words.
+	generator Generator
+	// blind draws every typed character as if it were correct.
+	blind bool
+	// lesson is the progress shown above the text in lesson mode, nil
+	// otherwise.
+	lesson        *lesson.Progress
+	lessonTargets lesson.Targets
+	// shownAt is when the current test first appeared, for funboxes that
+	// hide the text after a while.
+	shownAt time.Time
+	// tickChain is the tick loop of the current test. Each Init starts a
+	// new loop and ticks from older ones are dropped.
+	tickChain int
+	mu        sync.RWMutex
 }
 
-func NewModel(config internal.GameConfig) *Model {
+func NewModel(keys keymap.TypingKeyMap, config internal.GameConfig) *Model {
 	test := internal.NewTest(config)
 	if test == nil {
 		test = internal.NewTest(internal.DefaultGameConfig())
 	}
 	return &Model{
-		config:      config,
-		currentTest: test,
+		keys:             keys,
+		config:           config,
+		currentTest:      test,
+		shownAt:          time.Now(),
+		visibleLines:     DefaultVisibleLines,
+		tapeCaretPercent: DefaultTapeCaretPct,
+		validity:         internal.DefaultValidityRules(),
 	}
 }
 
+func (m *Model) SetLayout(layout Layout, scroll TapeScroll, caretPercent int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.layout = layout
+	m.tapeScroll = scroll
+	m.tapeCaretPercent = caretPercent
+}
+
+// SetVisibleLines sets how many wrapped lines the typing box shows at
+// once. Zero or less shows the whole text.
+func (m *Model) SetVisibleLines(lines int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.visibleLines = lines
+}
+
 func (m *Model) Init() tea.Cmd {
-	return shared.TickEvery()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tickChain++
+	return shared.TickEvery(m.tickChain)
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -45,10 +112,18 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case shared.TickMsg:
 		m.mu.Lock()
+		if msg.Chain != m.tickChain {
+			m.mu.Unlock()
+			return m, nil
+		}
 		if m.currentTest != nil && !m.currentTest.StartTime.IsZero() {
 			wpm := internal.CalculateWPM(m.currentTest)
 			m.realTimeWPM = wpm
 
+			if reason := internal.CheckMinimums(m.currentTest); reason != nil {
+				internal.FailTest(m.currentTest, reason, m.currentTest.CurrentPos)
+			}
+
 			if m.isInLastFiveSeconds() {
 				if m.fadeStartTime.IsZero() {
 					m.fadeStartTime = time.Now()
@@ -58,27 +133,37 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		m.mu.Unlock()
-		return m, shared.TickEvery()
+		return m, shared.TickEvery(msg.Chain)
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "backspace":
+		m.mu.Lock()
+		m.lastKeystroke = time.Now()
+		m.notice = ""
+		m.mu.Unlock()
+
+		switch {
+		case key.Matches(msg, m.keys.Pause):
 			m.mu.Lock()
-			if m.currentTest != nil {
-				internal.ProcessBackspace(m.currentTest)
+			if internal.IsPaused(m.currentTest) {
+				internal.ResumeTest(m.currentTest)
+			} else {
+				internal.PauseTest(m.currentTest)
 			}
 			m.mu.Unlock()
 			return m, nil
+		case key.Matches(msg, m.keys.Backspace):
+			m.mu.Lock()
+			internal.ProcessBackspace(m.currentTest)
+			m.mu.Unlock()
+			return m, nil
+		case key.Matches(msg, m.keys.DeleteWord):
+			m.mu.Lock()
+			internal.ProcessWordBackspace(m.currentTest)
+			m.mu.Unlock()
+			return m, nil
 		default:
 			m.mu.Lock()
-			if m.currentTest != nil {
-				msgStr := msg.String()
-				runes := []rune(msgStr)
-				if len(runes) == 1 {
-					char := runes[0]
-					internal.ProcessCharacter(m.currentTest, char)
-				}
-			}
+			m.typeKey(msg)
 			m.mu.Unlock()
 			return m, nil
 		}
@@ -92,22 +177,212 @@ func (m *Model) IsCompleted() bool {
 }
 
 func (m *Model) GetResult() *internal.TestResult {
-	if m.currentTest != nil && m.currentTest.Completed {
-		return internal.GenerateResult(m.currentTest)
+	if m.currentTest == nil || !m.currentTest.Completed {
+		return nil
+	}
+	result := internal.GenerateResult(m.currentTest)
+	if result.Failed != "" {
+		return result
 	}
-	return nil
+	if err := internal.CheckValidity(m.currentTest, result, m.validity); err != nil {
+		result.Invalid = err.Error()
+	}
+	return result
+}
+
+func (m *Model) SetValidityRules(rules internal.ValidityRules) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.validity = rules
 }
 
 func (m *Model) Reset() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	test := internal.NewTest(m.config)
+	test := m.newTest()
 	if test == nil {
 		test = internal.NewTest(internal.DefaultGameConfig())
 	}
+	m.begin(test)
+}
+
+// newTest generates fresh words and runs them through the funboxes.
+func (m *Model) newTest() *internal.TypingTest {
+	var test *internal.TypingTest
+	if m.generator != nil && m.config.WordCount > 0 {
+		test = internal.NewTestWithWords(m.config, m.generator.Words(m.config.WordCount))
+	} else {
+		test = internal.NewTest(m.config)
+	}
+	return m.applyFunboxes(test)
+}
+
+// applyFunboxes rebuilds test over its words run through the funboxes,
+// so the text matches the funboxes the test records. Must be called with
+// m.mu held.
+func (m *Model) applyFunboxes(test *internal.TypingTest) *internal.TypingTest {
+	if test == nil || len(m.funboxes) == 0 {
+		return test
+	}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return internal.NewTestWithWords(m.config, m.funboxes.Apply(test.Words, rng))
+}
+
+// begin swaps in a fresh test. Must be called with m.mu held.
+func (m *Model) begin(test *internal.TypingTest) {
 	m.currentTest = test
+	m.shownAt = time.Now()
 	m.realTimeWPM = DefaultWPM
 	m.fadeStartTime = time.Time{}
+	m.notice = ""
+}
+
+// Repeat restarts the current test with the same words.
+func (m *Model) Repeat() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.currentTest == nil {
+		return
+	}
+	test := internal.NewTestWithWords(m.config, m.currentTest.Words)
+	if test == nil {
+		return
+	}
+	test.Quote = m.currentTest.Quote
+	test.Mode = m.currentTest.Mode
+	test.Exercise = m.currentTest.Exercise
+	m.begin(test)
+}
+
+// StartQuote starts a test over the words of q.
+func (m *Model) StartQuote(q quote.Quote) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	test := internal.NewTestWithWords(m.config, m.funboxes.Apply(q.Words(), rng))
+	if test == nil {
+		return
+	}
+	test.Quote = &internal.QuoteRef{ID: q.ID, Language: q.Language, Source: q.Source}
+	test.Mode = internal.ModeQuote
+	m.begin(test)
+}
+
+// Practice starts a test drawn from the given words, e.g. the words
+// missed in the last test.
+func (m *Model) Practice(words []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	test := m.applyFunboxes(internal.NewPracticeTest(m.config, words))
+	if test == nil {
+		return
+	}
+	test.Mode = internal.ModePractice
+	m.begin(test)
+}
+
+// SetGameConfig changes the settings of the next test.
+func (m *Model) SetGameConfig(config internal.GameConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config = config
+}
+
+// SetFunboxes changes the funboxes of the next test. The game config
+// carries their names and whether the words run together.
+func (m *Model) SetFunboxes(set funbox.Set) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.funboxes = set
+}
+
+// StartExercise starts a test of exactly the given words, an exercise of
+// the curriculum lesson id. It reports false, keeping the current test,
+// when there is nothing to type.
+func (m *Model) StartExercise(id string, words []string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	test := m.applyFunboxes(internal.NewTestWithWords(m.config, words))
+	if test == nil {
+		return false
+	}
+	test.Mode = internal.ModeExercise
+	test.Exercise = id
+	m.begin(test)
+	return true
+}
+
+// Mode is where the text of the current test came from.
+func (m *Model) Mode() internal.Mode {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.currentTest == nil {
+		return m.config.Mode
+	}
+	return m.currentTest.Mode
+}
+
+// SetGenerator changes how new tests make their words. nil goes back to
+// random common words.
+func (m *Model) SetGenerator(generator Generator) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.generator = generator
+}
+
+// SetLesson shows progress through the alphabet above the text. nil
+// hides it.
+func (m *Model) SetLesson(progress *lesson.Progress, targets lesson.Targets) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lesson = progress
+	m.lessonTargets = targets
+}
+
+// SetBlind hides error feedback while typing.
+func (m *Model) SetBlind(blind bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blind = blind
+}
+
+// MissedWords lists the words typed with errors in the current test.
+func (m *Model) MissedWords() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return internal.MissedWords(m.currentTest)
+}
+
+// KeyTimings times each key of the current test, for lesson mode.
+func (m *Model) KeyTimings() map[rune]internal.KeyStat {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return internal.KeyTimings(m.currentTest)
+}
+
+// Elapsed is how long the typist has been typing in the current test.
+func (m *Model) Elapsed() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.currentTest == nil || m.currentTest.StartTime.IsZero() {
+		return 0
+	}
+	return internal.ActiveDuration(m.currentTest, time.Now())
+}
+
+// Pause stops the test clock until the next keystroke.
+func (m *Model) Pause() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	internal.PauseTest(m.currentTest)
+}
+
+// SetPrompt replaces the help line under the typing box, e.g. to ask for
+// confirmation. An empty prompt restores the help text.
+func (m *Model) SetPrompt(prompt string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prompt = prompt
 }
 
 func (m *Model) isInLastFiveSeconds() bool {
@@ -115,7 +390,7 @@ func (m *Model) isInLastFiveSeconds() bool {
 		return false
 	}
 
-	elapsed := time.Since(m.currentTest.StartTime)
+	elapsed := internal.ActiveDuration(m.currentTest, time.Now())
 	remaining := m.config.TestDuration - elapsed
 	return remaining <= time.Duration(FadeWarningTime)*time.Second && remaining > 0
 }
@@ -132,11 +407,18 @@ func (m *Model) getFadeFactor() float64 {
 
 func (m *Model) getCurrentCursorStyle() lipgloss.Style {
 	if !m.isInLastFiveSeconds() {
-		return shared.CursorStyle
+		return shared.Styles().Cursor
 	}
 	fadeFactor := m.getFadeFactor()
-	interpolatedColor := shared.InterpolateColor("#ffffff", "#ff0000", fadeFactor)
+	if shared.CurrentColorMode() < shared.ColorMode256 {
+		if fadeFactor > caretFadeMidpoint {
+			return shared.Styles().ErrorText.Reverse(true)
+		}
+		return shared.Styles().Cursor
+	}
+	activeTheme := shared.EffectiveTheme()
+	interpolatedColor := shared.InterpolateColor(activeTheme.Caret, activeTheme.Error, fadeFactor)
 	return lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#000000")).
+		Foreground(lipgloss.Color(activeTheme.Bg)).
 		Background(lipgloss.Color(interpolatedColor))
 }
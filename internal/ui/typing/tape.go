@@ -0,0 +1,129 @@
+package typing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"aiotype/internal"
+	"aiotype/internal/ui/shared"
+)
+
+var ErrInvalidLayout = errors.New("invalid layout")
+
+type Layout int
+
+const (
+	LayoutBox Layout = iota
+	LayoutTape
+)
+
+type TapeScroll int
+
+const (
+	TapeScrollLetter TapeScroll = iota
+	TapeScrollWord
+)
+
+func ParseLayout(value string) (Layout, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "box":
+		return LayoutBox, nil
+	case "tape":
+		return LayoutTape, nil
+	}
+	return LayoutBox, fmt.Errorf("%w: %q", ErrInvalidLayout, value)
+}
+
+func ParseTapeScroll(value string) (TapeScroll, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "letter":
+		return TapeScrollLetter, nil
+	case "word":
+		return TapeScrollWord, nil
+	}
+	return TapeScrollLetter, fmt.Errorf("%w: tape scroll %q", ErrInvalidLayout, value)
+}
+
+func (m *Model) calculateTapeLayout() LayoutConstraints {
+	totalReserved := BorderWidth + TextPadding + SidePadding
+	textAreaWidth := m.windowWidth - totalReserved
+	if textAreaWidth < MinTextWidth {
+		textAreaWidth = MinTextWidth
+	}
+	if textAreaWidth > MaxTextAreaWidth {
+		textAreaWidth = MaxTextAreaWidth
+	}
+
+	contentWidth := textAreaWidth + TextPadding
+	return LayoutConstraints{
+		minTextWidth:  MinTextWidth,
+		maxTextWidth:  MaxTextAreaWidth,
+		textAreaWidth: textAreaWidth,
+		contentWidth:  contentWidth,
+		boxWidth:      contentWidth + BorderWidth,
+		paddingTotal:  totalReserved,
+		safetyMargin:  SidePadding,
+	}
+}
+
+// tapeAnchor is the text index pinned under the caret column. Scrolling
+// by letter pins the caret itself; scrolling by word pins the start of the
+// current word, so the text only moves once a word is finished.
+func (m *Model) tapeAnchor() int {
+	position := m.currentTest.CurrentPos
+	if m.tapeScroll == TapeScrollLetter {
+		return position
+	}
+
+	unitIndex := internal.GetWordIndexForPosition(m.currentTest, position)
+	if unitIndex == -1 {
+		return position
+	}
+	return m.currentTest.WordStatuses[unitIndex].StartIndex
+}
+
+func (m *Model) renderTape(width int) string {
+	text := []rune(m.currentTest.TargetText)
+
+	caretColumn := width * m.tapeCaretPercent / 100
+	if caretColumn >= width {
+		caretColumn = width - 1
+	}
+	if caretColumn < 0 {
+		caretColumn = 0
+	}
+
+	start := m.tapeAnchor() - caretColumn
+	leftPad := 0
+	if start < 0 {
+		leftPad = -start
+		start = 0
+	}
+	end := start + width - leftPad
+	if end > len(text) {
+		end = len(text)
+	}
+	visible := string(text[start:end])
+
+	var result strings.Builder
+	expectedChar := m.expectedCharPosition()
+
+	if expectedChar == shared.ExpectedCharAbove {
+		result.WriteString(strings.Repeat(" ", leftPad))
+		m.renderExpectedHints(visible, &result, start)
+		result.WriteString("\n")
+	}
+
+	result.WriteString(strings.Repeat(" ", leftPad))
+	charIndex := start
+	m.renderLineWithHighlighting(visible, &result, &charIndex)
+
+	if expectedChar == shared.ExpectedCharBelow {
+		result.WriteString("\n")
+		result.WriteString(strings.Repeat(" ", leftPad))
+		m.renderExpectedHints(visible, &result, start)
+	}
+
+	return result.String()
+}
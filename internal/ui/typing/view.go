@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"aiotype/internal"
+	"aiotype/internal/funbox"
+	"aiotype/internal/ui/keymap"
 	"aiotype/internal/ui/shared"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -14,18 +18,45 @@ func (m *Model) View() string {
 		return ""
 	}
 
-	if m.windowWidth < MinTerminalWidth || m.windowHeight < MinTerminalHeight {
+	minWidth, minHeight := MinTerminalWidth, MinTerminalHeight
+	if m.layout == LayoutTape {
+		minWidth, minHeight = MinTapeTerminalWidth, MinTapeTerminalHeight
+	}
+
+	if m.windowWidth < minWidth || m.windowHeight < minHeight {
 		return lipgloss.Place(
 			m.windowWidth,
 			m.windowHeight,
 			lipgloss.Center,
 			lipgloss.Center,
-			lipgloss.NewStyle().Foreground(shared.RedTextStyle.GetForeground()).Render("Terminal too small"),
+			lipgloss.NewStyle().Foreground(shared.Styles().ErrorText.GetForeground()).Render("Terminal too small"),
 		)
 	}
 
 	typingArea := m.renderTypingArea()
-	help := shared.HelpStyle.Render("ESC to return to menu • CTRL+C to quit")
+
+	// Tape layout is meant for short tmux panes, so drop the chrome around
+	// the box when it would push the box off screen.
+	if m.layout == LayoutTape && m.windowHeight < CompactTerminalHeight {
+		return lipgloss.Place(
+			m.windowWidth,
+			m.windowHeight,
+			lipgloss.Center,
+			lipgloss.Center,
+			typingArea,
+		)
+	}
+
+	help := shared.HelpLine(m.keys)
+	switch {
+	case m.prompt != "":
+		help = shared.Styles().Selected.MarginTop(1).Render(m.prompt)
+	case m.notice != "":
+		help = shared.Styles().ErrorText.MarginTop(1).Render(m.notice)
+	case internal.IsPaused(m.currentTest):
+		help = shared.Styles().Selected.MarginTop(1).Render(
+			"Paused • keep typing or press " + keymap.Describe(m.keys.Pause) + " to resume")
+	}
 
 	content := lipgloss.JoinVertical(
 		lipgloss.Center,
@@ -33,6 +64,9 @@ func (m *Model) View() string {
 		"",
 		help,
 	)
+	if header := m.renderLessonHeader(); header != "" {
+		content = lipgloss.JoinVertical(lipgloss.Center, header, "", content)
+	}
 
 	responsivePadding := BasePadding
 	if m.windowWidth > LargeScreenWidth {
@@ -41,7 +75,7 @@ func (m *Model) View() string {
 
 	styledContent := lipgloss.NewStyle().
 		Padding(responsivePadding, 0).
-		Foreground(shared.WhiteTextStyle.GetForeground()).
+		Foreground(shared.Styles().Text.GetForeground()).
 		Render(content)
 
 	return lipgloss.Place(
@@ -61,9 +95,17 @@ func (m *Model) renderTypingArea() string {
 		return ""
 	}
 
-	constraints := m.calculateOptimalLayout()
-	wrappedLines := m.wrapText(m.currentTest.TargetText, constraints.textAreaWidth)
-	rendered := m.renderTextWithHighlighting(wrappedLines)
+	var constraints LayoutConstraints
+	var rendered string
+	if m.layout == LayoutTape {
+		constraints = m.calculateTapeLayout()
+		rendered = m.renderTape(constraints.textAreaWidth - m.caretColumns())
+	} else {
+		constraints = m.calculateOptimalLayout()
+		wrappedLines := m.wrapText(m.currentTest.TargetText, constraints.textAreaWidth-m.caretColumns())
+		firstLine, lineCount := m.visibleLineRange(wrappedLines)
+		rendered = m.renderTextWithHighlighting(wrappedLines, firstLine, lineCount)
+	}
 	processedContent := m.processContentForBorder(rendered, constraints)
 	timeLeft := m.formatTimeRemaining()
 	wpmText := fmt.Sprintf("%.0f", m.realTimeWPM)
@@ -76,7 +118,7 @@ func (m *Model) formatTimeRemaining() string {
 		return fmt.Sprintf("%.0fs", m.config.TestDuration.Seconds())
 	}
 
-	elapsed := time.Since(m.currentTest.StartTime)
+	elapsed := internal.ActiveDuration(m.currentTest, time.Now())
 	remaining := m.config.TestDuration - elapsed
 	if remaining <= 0 {
 		return "0s"
@@ -84,57 +126,179 @@ func (m *Model) formatTimeRemaining() string {
 	return fmt.Sprintf("%.0fs", remaining.Seconds())
 }
 
-func (m *Model) renderTextWithHighlighting(wrappedLines []string) string {
+// visibleLineRange picks the window of wrapped lines shown in the box.
+// Like monkeytype, the caret stays on the first line until it reaches the
+// second, and from then on the text scrolls to keep it on the second line.
+func (m *Model) visibleLineRange(wrappedLines []string) (firstLine, lineCount int) {
+	visible := m.visibleLines
+	if visible <= 0 || visible >= len(wrappedLines) {
+		return 0, len(wrappedLines)
+	}
+
+	cursorLine := cursorLineIndex(wrappedLines, m.currentTest.CurrentPos)
+	firstLine = cursorLine - 1
+	if visible == 1 {
+		firstLine = cursorLine
+	}
+	if firstLine > len(wrappedLines)-visible {
+		firstLine = len(wrappedLines) - visible
+	}
+	if firstLine < 0 {
+		firstLine = 0
+	}
+
+	return firstLine, visible
+}
+
+func cursorLineIndex(wrappedLines []string, position int) int {
+	lineStart := 0
+	for i, line := range wrappedLines {
+		lineEnd := lineStart + utf8.RuneCountInString(line)
+		if position < lineEnd {
+			return i
+		}
+		lineStart = lineEnd
+	}
+	return len(wrappedLines) - 1
+}
+
+func (m *Model) renderTextWithHighlighting(wrappedLines []string, firstLine, lineCount int) string {
 	if m.currentTest == nil || len(wrappedLines) == 0 {
 		return strings.Join(wrappedLines, "\n")
 	}
 
 	var result strings.Builder
 	charIndex := 0
+	for _, line := range wrappedLines[:firstLine] {
+		charIndex += utf8.RuneCountInString(line)
+	}
+	expectedChar := m.expectedCharPosition()
 
-	for lineIndex, line := range wrappedLines {
+	for lineIndex, line := range wrappedLines[firstLine : firstLine+lineCount] {
 		if lineIndex > 0 {
 			result.WriteString("\n")
 		}
+		lineStart := charIndex
+		if expectedChar == shared.ExpectedCharAbove {
+			m.renderExpectedHints(line, &result, lineStart)
+			result.WriteString("\n")
+		}
 		m.renderLineWithHighlighting(line, &result, &charIndex)
+		if expectedChar == shared.ExpectedCharBelow {
+			result.WriteString("\n")
+			m.renderExpectedHints(line, &result, lineStart)
+		}
 	}
 
 	return result.String()
 }
 
+// expectedCharPosition is where expected characters are shown, which is
+// nowhere in blind mode.
+func (m *Model) expectedCharPosition() shared.ExpectedCharPosition {
+	if m.blind {
+		return shared.ExpectedCharOff
+	}
+	return shared.CurrentAccessibility().ExpectedChar
+}
+
+// renderExpectedHints writes a row that shows, for every mistyped
+// character in line, the character that was expected in that column.
+func (m *Model) renderExpectedHints(line string, result *strings.Builder, lineStart int) {
+	target := []rune(m.currentTest.TargetText)
+	for column := range utf8.RuneCountInString(line) {
+		charIndex := m.funboxes.Source(m.currentTest, lineStart+column)
+		if charIndex == m.currentTest.CurrentPos {
+			result.WriteString(strings.Repeat(" ", m.caretColumns()))
+		}
+		if charIndex < len(m.currentTest.TypedChars) && !m.currentTest.TypedChars[charIndex].IsCorrect {
+			char := target[charIndex]
+			if char == ' ' {
+				char = shared.WrongSpaceGlyph
+			}
+			result.WriteString(shared.Styles().ExpectedHint.Render(string(char)))
+		} else {
+			result.WriteString(" ")
+		}
+	}
+}
+
+// renderLineWithHighlighting draws line, whose first character sits at
+// *charIndex in the text. Funboxes may draw another character of the text
+// in a column, or blank it out.
 func (m *Model) renderLineWithHighlighting(line string, result *strings.Builder, charIndex *int) {
-	for _, char := range line {
-		m.renderCharacterWithStyle(char, result, *charIndex)
+	target := []rune(m.currentTest.TargetText)
+	view := funbox.View{Test: m.currentTest, Shown: time.Since(m.shownAt)}
+	for range utf8.RuneCountInString(line) {
+		source := m.funboxes.Source(m.currentTest, *charIndex)
+		char := target[source]
+		if m.funboxes.Hidden(view, source) {
+			char = ' '
+		}
+		m.renderCharacterWithStyle(char, result, source)
 		*charIndex++
 	}
 }
 
 func (m *Model) renderCharacterWithStyle(char rune, result *strings.Builder, charIndex int) {
+	if charIndex == m.currentTest.CurrentPos && charIndex >= len(m.currentTest.TypedChars) {
+		if rejected := m.currentTest.Rejected; rejected != 0 && !m.blind {
+			m.renderRejected(rejected, result)
+			return
+		}
+		m.renderCaret(char, result)
+		return
+	}
+
+	display, style := string(char), shared.Styles().SubText
 	if charIndex < len(m.currentTest.TypedChars) {
-		m.renderTypedCharacter(char, result, charIndex)
-	} else if charIndex == m.currentTest.CurrentPos {
-		cursorStyle := m.getCurrentCursorStyle()
-		result.WriteString(cursorStyle.Render(string(char)))
-	} else {
-		result.WriteString(shared.GrayTextStyle.Render(string(char)))
+		display, style = m.typedCharacterStyle(charIndex)
+	}
+	if charIndex == m.paceCaretIndex() {
+		style = shared.Styles().PaceCaret.Inherit(style)
+	}
+	result.WriteString(style.Render(display))
+}
+
+// renderRejected draws a key refused by stop on error on the caret, so the
+// typist sees what they pressed without the caret moving on.
+func (m *Model) renderRejected(char rune, result *strings.Builder) {
+	display := string(char)
+	if char == ' ' {
+		display = string(shared.WrongSpaceGlyph)
 	}
+	if m.caretStyle == CaretLine {
+		result.WriteString(m.getCaretAccentStyle().Render(CaretLineGlyph))
+	}
+	result.WriteString(shared.Styles().ErrorText.Reverse(true).Render(display))
 }
 
-func (m *Model) renderTypedCharacter(char rune, result *strings.Builder, charIndex int) {
+func (m *Model) typedCharacterStyle(charIndex int) (string, lipgloss.Style) {
 	typedChar := m.currentTest.TypedChars[charIndex]
+	if m.blind {
+		// Show the text as it should read, so a wrong letter gives
+		// nothing away either.
+		return string([]rune(m.currentTest.TargetText)[charIndex]), shared.Styles().Text
+	}
 	isInErrorUnit := m.isCharacterInErrorUnit(charIndex)
 
+	display := string(typedChar.Character)
+	if !typedChar.IsCorrect && typedChar.Character == ' ' &&
+		shared.CurrentAccessibility().ErrorMarking == shared.ErrorMarkGlyph {
+		display = string(shared.WrongSpaceGlyph)
+	}
+
+	styles := shared.Styles()
 	if isInErrorUnit {
 		if typedChar.IsCorrect {
-			result.WriteString(shared.WhiteTextRedBgStyle.Render(string(typedChar.Character)))
-		} else {
-			result.WriteString(shared.RedTextRedBgStyle.Render(string(typedChar.Character)))
+			return display, styles.TextInErrorWord
 		}
-	} else if typedChar.IsCorrect {
-		result.WriteString(shared.WhiteTextStyle.Render(string(typedChar.Character)))
-	} else {
-		result.WriteString(shared.RedTextStyle.Render(string(typedChar.Character)))
+		return display, styles.ErrorInErrorWord
+	}
+	if typedChar.IsCorrect {
+		return display, styles.Text
 	}
+	return display, styles.ErrorText
 }
 
 func (m *Model) isCharacterInErrorUnit(charIndex int) bool {
@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var (
+	ErrAFK           = errors.New("afk")
+	ErrLowAccuracy   = errors.New("accuracy too low")
+	ErrRawAboveNet   = errors.New("raw wpm far above wpm")
+	ErrUniformTiming = errors.New("keystroke timing too uniform")
+	ErrPasted        = errors.New("pasted input")
+)
+
+const (
+	// minTimingSamples is how many gaps the timing check needs before a
+	// low spread means anything.
+	minTimingSamples = 20
+	// minRawGapWPM keeps the raw ratio rule from firing on slow tests,
+	// where a few corrections are already a large ratio.
+	minRawGapWPM = 10.0
+)
+
+// ValidityRules decide whether a finished test counts. A zero value turns
+// its rule off.
+type ValidityRules struct {
+	// AFKGap is the longest pause between two keystrokes.
+	AFKGap time.Duration
+	// MinAccuracy is a percentage.
+	MinAccuracy float64
+	// MaxRawRatio caps raw WPM as a multiple of WPM, which catches
+	// mashing keys and deleting the garbage.
+	MaxRawRatio float64
+	// MinTimingSpread is the lowest coefficient of variation allowed for
+	// the gaps between keystrokes. People are never metronomes; macros and
+	// pasted input are.
+	MinTimingSpread float64
+}
+
+func DefaultValidityRules() ValidityRules {
+	return ValidityRules{
+		AFKGap:          15 * time.Second,
+		MinAccuracy:     75,
+		MaxRawRatio:     1.5,
+		MinTimingSpread: 0.1,
+	}
+}
+
+// CheckValidity returns why a completed test is invalid, or nil if every
+// rule passes. Pasted input always makes a test invalid.
+func CheckValidity(test *TypingTest, result *TestResult, rules ValidityRules) error {
+	if test == nil || result == nil {
+		return ErrTestNil
+	}
+
+	for _, keystroke := range test.Keystrokes {
+		if keystroke.Pasted {
+			return ErrPasted
+		}
+	}
+
+	gaps := keystrokeGaps(test)
+
+	if rules.AFKGap > 0 {
+		for _, gap := range gaps {
+			if gap > rules.AFKGap {
+				return fmt.Errorf("%w: no input for %.0fs", ErrAFK, gap.Seconds())
+			}
+		}
+	}
+
+	if rules.MinAccuracy > 0 && result.Accuracy < rules.MinAccuracy {
+		return fmt.Errorf("%w: %.1f%% is under %.0f%%", ErrLowAccuracy, result.Accuracy, rules.MinAccuracy)
+	}
+
+	if rules.MaxRawRatio > 0 && result.RawWPM-result.WPM > minRawGapWPM &&
+		result.RawWPM > result.WPM*rules.MaxRawRatio {
+		return fmt.Errorf("%w: %.0f raw against %.0f", ErrRawAboveNet, result.RawWPM, result.WPM)
+	}
+
+	if rules.MinTimingSpread > 0 && len(gaps) >= minTimingSamples {
+		if spread := variation(gaps); spread < rules.MinTimingSpread {
+			return fmt.Errorf("%w: spread %.2f", ErrUniformTiming, spread)
+		}
+	}
+
+	return nil
+}
+
+func keystrokeGaps(test *TypingTest) []time.Duration {
+	if len(test.Keystrokes) < 2 {
+		return nil
+	}
+	gaps := make([]time.Duration, 0, len(test.Keystrokes)-1)
+	for i := 1; i < len(test.Keystrokes); i++ {
+		gaps = append(gaps, test.Keystrokes[i].Offset-test.Keystrokes[i-1].Offset)
+	}
+	return gaps
+}
+
+// variation is the coefficient of variation, the standard deviation as a
+// fraction of the mean.
+func variation(gaps []time.Duration) float64 {
+	mean := 0.0
+	for _, gap := range gaps {
+		mean += gap.Seconds()
+	}
+	mean /= float64(len(gaps))
+	if mean <= 0 {
+		return 0
+	}
+
+	variance := 0.0
+	for _, gap := range gaps {
+		d := gap.Seconds() - mean
+		variance += d * d
+	}
+	variance /= float64(len(gaps))
+	return math.Sqrt(variance) / mean
+}
@@ -0,0 +1,279 @@
+// Package weakness builds practice text from the typist's own history. It
+// finds the keys they reach slowest and the key pairs and words they miss
+// most, then over-samples words that contain them. The profile is rebuilt
+// from the latest tests before every test, so practice moves on as the
+// weaknesses improve.
+package weakness
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"aiotype/internal"
+	"aiotype/internal/history"
+)
+
+const (
+	// Window is how many recent tests with keystroke statistics the
+	// profile reads.
+	Window = 20
+	// decay is how much less each test counts than the one after it, so
+	// the latest tests say the most.
+	decay = 0.85
+	// minAttempts is how often a key or pair must have come up before it
+	// is judged.
+	minAttempts = 5
+	// missWeight is how much a miss rate of 100% scores against a key,
+	// next to being twice as slow as average, which scores 1.
+	missWeight = 4
+	// boost is how much more often than other words a word with a
+	// weakness of score 1 comes up.
+	boost = 3
+	// missedWordWeight is the weight of a missed word of the history
+	// against an ordinary word's 1.
+	missedWordWeight = 5
+
+	maxKeys    = 6
+	maxBigrams = 6
+	maxWords   = 10
+)
+
+// Weakness is a key, pair of keys or word and how weak it is. Higher is
+// weaker.
+type Weakness struct {
+	Name  string
+	Score float64
+}
+
+// Profile is the weaknesses found in recent history, the weakest first.
+type Profile struct {
+	Keys    []Weakness
+	Bigrams []Weakness
+	Words   []Weakness
+}
+
+func (p Profile) Empty() bool {
+	return len(p.Keys) == 0 && len(p.Bigrams) == 0 && len(p.Words) == 0
+}
+
+// String lists the weaknesses briefly, e.g. `keys e r · pairs th · words
+// because`.
+func (p Profile) String() string {
+	var parts []string
+	for _, group := range []struct {
+		label      string
+		weaknesses []Weakness
+	}{{"keys", p.Keys}, {"pairs", p.Bigrams}, {"words", p.Words}} {
+		if len(group.weaknesses) == 0 {
+			continue
+		}
+		names := make([]string, 0, 3)
+		for _, w := range group.weaknesses[:min(3, len(group.weaknesses))] {
+			names = append(names, w.Name)
+		}
+		parts = append(parts, group.label+" "+strings.Join(names, " "))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// tally sums statistics over several tests, each weighted by its age.
+type tally struct {
+	hits, misses float64
+	time, timed  float64
+}
+
+func (t *tally) add(stat internal.KeyStat, weight float64) {
+	t.hits += float64(stat.Hits) * weight
+	t.misses += float64(stat.Misses) * weight
+	t.time += float64(stat.Time) * weight
+	t.timed += float64(stat.Timed) * weight
+}
+
+func (t tally) attempts() float64 {
+	return t.hits + t.misses
+}
+
+func (t tally) average() float64 {
+	if t.timed == 0 {
+		return 0
+	}
+	return t.time / t.timed
+}
+
+// score rates a tally by its miss rate and by how much slower than
+// average it is.
+func (t tally) score(average float64) float64 {
+	score := missWeight * t.misses / t.attempts()
+	if average > 0 && t.timed > 0 {
+		score += max(0, t.average()/average-1)
+	}
+	return score
+}
+
+// Analyze builds a profile from the latest records. Records from before
+// keystroke statistics were stored are skipped, as are tests whose text
+// was not plain words.
+func Analyze(records []history.Record) Profile {
+	keys := map[string]*tally{}
+	bigrams := map[string]*tally{}
+	words := map[string]float64{}
+	var overall tally
+
+	weight := 1.0
+	read := 0
+	for i := len(records) - 1; i >= 0 && read < Window; i-- {
+		record := records[i]
+		if len(record.Keys) == 0 || !plainWords(record) {
+			continue
+		}
+		read++
+		stats := record.KeyStats()
+		for key, stat := range stats.Keys {
+			name := string(key)
+			if keys[name] == nil {
+				keys[name] = &tally{}
+			}
+			keys[name].add(stat, weight)
+			overall.add(stat, weight)
+		}
+		for bigram, stat := range stats.Bigrams {
+			if bigrams[bigram] == nil {
+				bigrams[bigram] = &tally{}
+			}
+			bigrams[bigram].add(stat, weight)
+		}
+		for _, word := range record.MissedWords {
+			if isWord(word) {
+				words[word] += weight
+			}
+		}
+		weight *= decay
+	}
+
+	average := overall.average()
+	profile := Profile{
+		Keys:    rank(keys, average, maxKeys),
+		Bigrams: rank(bigrams, average, maxBigrams),
+	}
+	for word, score := range words {
+		profile.Words = append(profile.Words, Weakness{Name: word, Score: score})
+	}
+	sortWeaknesses(profile.Words)
+	if len(profile.Words) > maxWords {
+		profile.Words = profile.Words[:maxWords]
+	}
+	return profile
+}
+
+// plainWords reports whether a record was typed from ordinary words, so
+// its keys and missed words make sense as practice. Funboxes change the
+// text, quotes carry punctuation, and lessons and exercises type
+// pseudo-words and drills.
+func plainWords(record history.Record) bool {
+	if len(record.Funboxes) > 0 {
+		return false
+	}
+	switch record.Mode {
+	case "", internal.ModeWeakness.String(), internal.ModePractice.String():
+		return true
+	}
+	return false
+}
+
+// isWord keeps missed words that are lower case letters, with an
+// apostrophe at most, so no stray punctuation becomes practice text.
+func isWord(word string) bool {
+	if word == "" {
+		return false
+	}
+	for _, r := range word {
+		if r != '\'' && !unicode.IsLower(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func rank(tallies map[string]*tally, average float64, limit int) []Weakness {
+	var ranked []Weakness
+	for name, t := range tallies {
+		if t.attempts() < minAttempts {
+			continue
+		}
+		if score := t.score(average); score > 0 {
+			ranked = append(ranked, Weakness{Name: name, Score: score})
+		}
+	}
+	sortWeaknesses(ranked)
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
+
+func sortWeaknesses(weaknesses []Weakness) {
+	sort.Slice(weaknesses, func(i, j int) bool {
+		if weaknesses[i].Score != weaknesses[j].Score {
+			return weaknesses[i].Score > weaknesses[j].Score
+		}
+		return weaknesses[i].Name < weaknesses[j].Name
+	})
+}
+
+// Generator draws practice words weighted towards a profile.
+type Generator struct {
+	pool    []string
+	weights []float64
+	window  int
+}
+
+// NewGenerator weights every word of pool by the weak keys and pairs it
+// contains, and adds the missed words of the profile. window keeps words
+// from repeating, as for random words.
+func NewGenerator(profile Profile, pool []string, window int) *Generator {
+	g := &Generator{window: window}
+	seen := make(map[string]bool, len(pool))
+	for _, word := range pool {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		g.pool = append(g.pool, word)
+		g.weights = append(g.weights, 1+boost*wordScore(profile, word))
+	}
+	for _, w := range profile.Words {
+		weight := missedWordWeight * w.Score
+		if i := slices.Index(g.pool, w.Name); i >= 0 {
+			g.weights[i] = max(g.weights[i], weight)
+			continue
+		}
+		g.pool = append(g.pool, w.Name)
+		g.weights = append(g.weights, weight)
+	}
+	return g
+}
+
+// wordScore sums the weaknesses found in word, each once.
+func wordScore(profile Profile, word string) float64 {
+	score := 0.0
+	for _, w := range profile.Keys {
+		if strings.Contains(word, w.Name) {
+			score += w.Score
+		}
+	}
+	for _, w := range profile.Bigrams {
+		if strings.Contains(word, w.Name) {
+			score += w.Score
+		}
+	}
+	return score
+}
+
+func (g *Generator) Words(n int) []string {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return internal.DrawWords(g.pool, g.weights, g.window, n, rng)
+}
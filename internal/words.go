@@ -0,0 +1,191 @@
+package internal
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+//go:embed words.txt
+var rankedWordsFile string
+
+// rankedWords are the thousand most common English words, most frequent
+// first.
+var rankedWords = strings.Fields(rankedWordsFile)
+
+// commonWords are the few hundred everyday words random tests have always
+// drawn from, roughly most frequent first. They are the default pool, so
+// default results stay comparable with older history.
+var commonWords = []string{
+	"the", "be", "to", "of", "and", "a", "in", "that", "have", "i",
+	"it", "for", "not", "on", "with", "he", "as", "you", "do", "at",
+	"this", "but", "his", "by", "from", "they", "we", "say", "her", "she",
+	"or", "an", "will", "my", "one", "all", "would", "there", "their", "what",
+	"so", "up", "out", "if", "about", "who", "get", "which", "go", "me",
+	"when", "make", "can", "like", "time", "no", "just", "him", "know", "take",
+	"people", "into", "year", "your", "good", "some", "could", "them", "see", "other",
+	"than", "then", "now", "look", "only", "come", "its", "over", "think", "also",
+	"back", "use", "two", "how", "our", "work", "first", "well", "way",
+	"even", "new", "want", "because", "these", "give", "day", "most", "us",
+	"is", "water", "long", "very", "still", "through", "down",
+	"may", "such", "here", "were", "been", "much",
+	"where", "too", "each", "many",
+	"has", "more", "life", "should",
+	"being", "made", "before", "might", "did", "every", "large", "often",
+	"together", "asked", "house", "don't", "world", "going", "school", "important", "until", "form",
+	"food", "keep", "children", "feet", "land", "side", "without", "boy", "once", "animal",
+	"enough", "took", "sometimes", "four", "head", "above", "kind", "began", "almost",
+	"live", "page", "got", "earth", "need", "far", "hand", "high", "mother",
+	"light", "country", "father", "let", "night", "picture", "study", "second", "soon",
+	"story", "since", "white", "ever", "paper", "hard", "near", "sentence", "better", "best",
+	"across", "during", "today", "however", "sure", "knew", "it's", "try", "told", "young",
+	"sun", "thing", "whole", "hear", "example", "heard", "several", "change", "answer", "room",
+	"sea", "against", "top", "turned", "learn", "point", "city", "play", "toward", "five",
+	"himself", "usually", "money", "seen", "didn't", "car", "morning", "i'm", "body", "upon",
+	"family", "later", "turn", "move", "face", "door", "cut", "done", "group", "true",
+	"leave", "color", "red", "friend", "pretty", "eat", "front", "feel", "fact",
+	"week", "eye", "same", "another", "left", "call", "while", "right",
+	"find", "part", "place", "under", "name", "help", "low", "line", "cause", "mean",
+	"differ", "old", "tell", "follow", "around", "three", "small", "set", "put", "end",
+	"why", "again", "off", "went", "number", "men", "found", "between", "home",
+	"big", "air", "own", "read", "last", "along", "next", "below", "saw",
+	"something", "thought", "both", "few", "those", "always", "looked",
+}
+
+var ErrInvalidSelection = errors.New("invalid word selection")
+
+const (
+	// selectionAttempts is how many draws a word gets to clear the repeat
+	// window before the last one is taken anyway, so a small pool cannot
+	// stall a test.
+	selectionAttempts = 20
+	// nearDuplicateSuffix is how many letters one word may add to the end
+	// of another and still count as the same word, as with "the", "then"
+	// and "there".
+	nearDuplicateSuffix = 3
+	// nearDuplicateStem is the shortest word checked for near duplicates,
+	// so "a" and "an" are still told apart.
+	nearDuplicateStem = 3
+)
+
+// WordSelection shapes how random words are drawn. The zero value draws
+// uniformly from commonWords and allows any repeat.
+type WordSelection struct {
+	// TopN draws from the most common words of the ranked list of a
+	// thousand. Zero draws from commonWords instead.
+	TopN int
+	// Zipf weights each word by 1/rank^Zipf, so common words come up as
+	// often as they do in real text. Zero draws uniformly.
+	Zipf float64
+	// Window is how many of the previous words a new word may not repeat
+	// or nearly repeat. Zero allows any repeat.
+	Window int
+}
+
+func (s WordSelection) Validate() error {
+	switch {
+	case s.TopN < 0:
+		return fmt.Errorf("%w: top %d words", ErrInvalidSelection, s.TopN)
+	case s.Zipf < 0:
+		return fmt.Errorf("%w: zipf %.2f, want 0 or more", ErrInvalidSelection, s.Zipf)
+	case s.Window < 0:
+		return fmt.Errorf("%w: repeat window %d", ErrInvalidSelection, s.Window)
+	}
+	return nil
+}
+
+// RankedWords is the built in word list, most frequent first.
+func RankedWords() []string {
+	return append([]string(nil), rankedWords...)
+}
+
+// randomPool is the list random tests draw from: commonWords, or the top
+// of rankedWords when the selection asks for them.
+func randomPool(selection WordSelection) []string {
+	if selection.TopN > 0 {
+		return rankedWords
+	}
+	return commonWords
+}
+
+// selectWords draws n words from pool, which is ranked most frequent
+// first.
+func selectWords(pool []string, selection WordSelection, n int, rng *rand.Rand) []string {
+	if selection.TopN > 0 && selection.TopN < len(pool) {
+		pool = pool[:selection.TopN]
+	}
+	weights := make([]float64, len(pool))
+	for rank := range weights {
+		weights[rank] = 1 / math.Pow(float64(rank+1), selection.Zipf)
+	}
+	return DrawWords(pool, weights, selection.Window, n, rng)
+}
+
+// DrawWords draws n words from pool, each in proportion to its weight,
+// keeping out repeats and near repeats of the window words before it.
+func DrawWords(pool []string, weights []float64, window, n int, rng *rand.Rand) []string {
+	if len(pool) == 0 || len(weights) != len(pool) {
+		return nil
+	}
+	cumulative := make([]float64, len(weights))
+	total := 0.0
+	for i, weight := range weights {
+		total += max(weight, 0)
+		cumulative[i] = total
+	}
+	if total == 0 {
+		return nil
+	}
+
+	words := make([]string, 0, n)
+	for len(words) < n {
+		recent := words[max(0, len(words)-window):]
+		var word string
+		for attempt := 0; attempt < selectionAttempts; attempt++ {
+			word = pool[weightedIndex(cumulative, rng)]
+			if !repeats(word, recent) {
+				break
+			}
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+func weightedIndex(cumulative []float64, rng *rand.Rand) int {
+	target := rng.Float64() * cumulative[len(cumulative)-1]
+	return sort.SearchFloat64s(cumulative, target)
+}
+
+func repeats(word string, recent []string) bool {
+	for _, previous := range recent {
+		if nearDuplicate(word, previous) {
+			return true
+		}
+	}
+	return false
+}
+
+// nearDuplicate reports whether a and b read as the same word: equal
+// apart from case and apostrophes, or one a short extension of the other.
+func nearDuplicate(a, b string) bool {
+	a, b = wordStem(a), wordStem(b)
+	if a == b {
+		return true
+	}
+	if utf8.RuneCountInString(a) > utf8.RuneCountInString(b) {
+		a, b = b, a
+	}
+	return utf8.RuneCountInString(a) >= nearDuplicateStem &&
+		strings.HasPrefix(b, a) &&
+		utf8.RuneCountInString(b)-utf8.RuneCountInString(a) <= nearDuplicateSuffix
+}
+
+func wordStem(word string) string {
+	return strings.ReplaceAll(strings.ToLower(word), "'", "")
+}